@@ -0,0 +1,214 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 别名冲突类型
+const (
+	AliasDuplicate = "duplicate"  // 同一别名配置在多个下注类型、体彩或关键字中
+	AliasNumberSet = "number_set" // 别名与号码集合的名称或别名相同
+	AliasShadowed  = "shadowed"   // 别名是其他类别中较长别名的一部分，出现在较长别名中时不会被单独识别
+)
+
+// 号码集合在别名分析中的类别名称
+const aliasCategoryNumberSet = "号码集合"
+
+// AliasConflict 别名冲突
+type AliasConflict struct {
+	Kind    string   `json:"kind"`    // 冲突类型
+	Keyword string   `json:"keyword"` // 冲突的别名（规范化后）
+	Owners  []string `json:"owners"`  // 使用该别名的配置项，如"下注类型\"三中三\""，按识别优先级排列，第一项生效
+	Within  string   `json:"within"`  // 包含该别名的较长别名，仅shadowed有
+	Profile string   `json:"profile"` // 所属的配置方案
+	Message string   `json:"message"` // 说明，有多个配置方案时注明所属方案
+}
+
+// AliasReport 别名分析结果
+type AliasReport struct {
+	Conflicts []AliasConflict `json:"conflicts"` // 重复的别名，保存下注类型、关键字别名时拒绝
+	Shadowed  []AliasConflict `json:"shadowed"`  // 被较长别名包含的别名，仅提示
+}
+
+// aliasKeyword 关键词前缀树中的一个关键词及其来源
+type aliasKeyword struct {
+	keyword  string // 规范化后的关键词
+	category string // 类别，如"下注类型"
+	name     string // 标准名称
+	alias    bool   // 是否为可配置的下注类型、体彩、关键字别名
+}
+
+// owner 配置项说明，如"下注类型\"三中三\""
+func (k aliasKeyword) owner() string {
+	return fmt.Sprintf("%s\"%s\"", k.category, k.name)
+}
+
+// analyzeAliases 分析所有配置方案的别名，当前方案在前；有多个配置方案时说明中注明所属方案
+func analyzeAliases(config *SystemConfig) AliasReport {
+	report := AliasReport{Conflicts: make([]AliasConflict, 0), Shadowed: make([]AliasConflict, 0)}
+	profiles := profileNames(config)
+	for _, info := range profiles {
+		profile, err := profileConfig(config, info.Name)
+		if err != nil {
+			continue
+		}
+		label := func(conflicts []AliasConflict) []AliasConflict {
+			for i := range conflicts {
+				conflicts[i].Profile = info.Name
+				if len(profiles) > 1 {
+					conflicts[i].Message = fmt.Sprintf("配置方案\"%s\"中%s", info.Name, conflicts[i].Message)
+				}
+			}
+			return conflicts
+		}
+		part := analyzeProfileAliases(profile)
+		report.Conflicts = append(report.Conflicts, label(part.Conflicts)...)
+		report.Shadowed = append(report.Shadowed, label(part.Shadowed)...)
+	}
+	return report
+}
+
+// analyzeProfileAliases 分析单个配置方案中的别名，按构建关键词前缀树的顺序（即识别优先级）收集所有关键词后检查：
+//   - 同一别名对应多个下注类型、体彩、关键字，或与号码集合的名称、别名相同：只有优先级最高的生效
+//   - 别名是其他类别中较长别名的一部分：按最长匹配识别，出现在较长别名中时不会被单独识别
+//
+// 号码集合之间的重复由validateNumberSets检查，内置的声明、集合运算关键词之间不检查
+func analyzeProfileAliases(config *SystemConfig) AliasReport {
+	keywords := collectAliasKeywords(newParserConfig(config, 0, time.Now()))
+	report := AliasReport{Conflicts: make([]AliasConflict, 0), Shadowed: make([]AliasConflict, 0)}
+
+	// 按关键词分组，保持识别优先级顺序
+	groups := make(map[string][]aliasKeyword)
+	for _, keyword := range keywords {
+		groups[keyword.keyword] = append(groups[keyword.keyword], keyword)
+	}
+
+	for _, text := range sortedKeys(groups) {
+		group := dedupeAliasOwners(groups[text])
+		if len(group) < 2 || !anyConfigurableAlias(group) {
+			continue
+		}
+		kind := AliasDuplicate
+		owners := make([]string, 0, len(group))
+		for _, keyword := range group {
+			owners = append(owners, keyword.owner())
+			if keyword.category == aliasCategoryNumberSet {
+				kind = AliasNumberSet
+			}
+		}
+		report.Conflicts = append(report.Conflicts, AliasConflict{
+			Kind:    kind,
+			Keyword: text,
+			Owners:  owners,
+			Message: fmt.Sprintf("\"%s\"同时是%s的别名，只有%s生效", text, strings.Join(owners, "、"), owners[0]),
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, short := range keywords {
+		for _, long := range keywords {
+			if short.keyword == long.keyword || !strings.Contains(long.keyword, short.keyword) ||
+				short.category == long.category || (!short.alias && !long.alias) {
+				continue
+			}
+			key := short.owner() + short.keyword + "|" + long.keyword
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			report.Shadowed = append(report.Shadowed, AliasConflict{
+				Kind:    AliasShadowed,
+				Keyword: short.keyword,
+				Owners:  []string{short.owner()},
+				Within:  long.keyword,
+				Message: fmt.Sprintf("%s的别名\"%s\"包含在%s的别名\"%s\"中，出现在\"%s\"中时不会被识别为%s",
+					short.owner(), short.keyword, long.owner(), long.keyword, long.keyword, short.owner()),
+			})
+		}
+	}
+	sort.SliceStable(report.Shadowed, func(i, j int) bool {
+		if report.Shadowed[i].Keyword != report.Shadowed[j].Keyword {
+			return report.Shadowed[i].Keyword < report.Shadowed[j].Keyword
+		}
+		return report.Shadowed[i].Within < report.Shadowed[j].Within
+	})
+
+	return report
+}
+
+// collectAliasKeywords 按newKeywordTrie的加入顺序收集关键词
+func collectAliasKeywords(config IntelligentBetParserConfig) []aliasKeyword {
+	keywords := make([]aliasKeyword, 0)
+	add := func(keyword, category, name string, alias bool) {
+		if keyword = normalizeText(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, aliasKeyword{keyword: keyword, category: category, name: name, alias: alias})
+		}
+	}
+	addAliases := func(category string, aliases map[string][]string) {
+		for _, name := range sortedKeys(aliases) {
+			add(name, category, name, true)
+			for _, alias := range aliases[name] {
+				add(alias, category, name, true)
+			}
+		}
+	}
+
+	for _, keyword := range sortedKeys(declarationKeywords) {
+		add(keyword, "声明关键词", declarationKeywords[keyword], false)
+	}
+	addAliases("下注类型", config.BetTypeAliases)
+	addAliases("体彩", config.LotteryAliases)
+	addAliases("模式", config.KeywordAliases)
+	addAliases("金额关键词", config.EndKeywords)
+	for _, set := range config.NumberSets {
+		for _, keyword := range append([]string{set.Name}, set.Aliases...) {
+			add(keyword, aliasCategoryNumberSet, set.Name, false)
+		}
+	}
+	for _, keyword := range sortedKeys(setOperatorKeywords) {
+		add(keyword, "集合运算", setOperatorKeywords[keyword], false)
+	}
+	return keywords
+}
+
+// dedupeAliasOwners 去掉同一配置项重复配置的同一别名
+func dedupeAliasOwners(group []aliasKeyword) []aliasKeyword {
+	result := make([]aliasKeyword, 0, len(group))
+	seen := make(map[string]bool)
+	for _, keyword := range group {
+		if owner := keyword.owner(); !seen[owner] {
+			seen[owner] = true
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// anyConfigurableAlias 是否包含可配置的下注类型、体彩、关键字别名
+func anyConfigurableAlias(group []aliasKeyword) bool {
+	for _, keyword := range group {
+		if keyword.alias {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAliasConflicts 任一配置方案存在重复的别名时返回错误，被较长别名包含的别名只记录日志
+func checkAliasConflicts(config *SystemConfig) error {
+	report := analyzeAliases(config)
+	for _, shadowed := range report.Shadowed {
+		safeLogger.AppendLog("别名提示: " + shadowed.Message)
+	}
+	if len(report.Conflicts) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(report.Conflicts))
+	for _, conflict := range report.Conflicts {
+		messages = append(messages, conflict.Message)
+	}
+	return fmt.Errorf("别名冲突: %s", strings.Join(messages, "；"))
+}
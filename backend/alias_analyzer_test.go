@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeAliasesAllProfiles(t *testing.T) {
+	config := getDefaultSystemConfig()
+	if report := analyzeAliases(config); len(report.Conflicts) > 0 {
+		t.Fatalf("默认配置不应有别名冲突: %+v", report.Conflicts)
+	}
+
+	// 未启用的方案中"死"同时是三中三、三中二的别名
+	if err := createProfile(config, "群A"); err != nil {
+		t.Fatal(err)
+	}
+	config.Profiles[0].BetTypeAliases.ThreeOfTwo = append(config.Profiles[0].BetTypeAliases.ThreeOfTwo, "死")
+
+	report := analyzeAliases(config)
+	if len(report.Conflicts) != 1 {
+		t.Fatalf("Conflicts = %+v, want 1", report.Conflicts)
+	}
+	conflict := report.Conflicts[0]
+	if conflict.Profile != "群A" || conflict.Keyword != "死" || !strings.Contains(conflict.Message, "配置方案\"群A\"") {
+		t.Errorf("conflict = %+v", conflict)
+	}
+	if err := checkAliasConflicts(config); err == nil {
+		t.Errorf("未启用方案的别名冲突应返回错误")
+	}
+
+	// 切换到该方案后仍报告冲突
+	if err := switchProfile(config, "群A"); err != nil {
+		t.Fatal(err)
+	}
+	if report := analyzeAliases(config); len(report.Conflicts) != 1 || report.Conflicts[0].Profile != "群A" {
+		t.Errorf("切换后 Conflicts = %+v", report.Conflicts)
+	}
+}
+
+func TestAnalyzeAliasesConflictKinds(t *testing.T) {
+	config := getDefaultSystemConfig()
+	config.KeywordAliases.OldMacau = append(config.KeywordAliases.OldMacau, "新澳")
+	config.BetTypeAliases.TwoOfTwo = append(config.BetTypeAliases.TwoOfTwo, "红")
+	config.KeywordAliases.Drag = append(config.KeywordAliases.Drag, "组")
+
+	report := analyzeAliases(config)
+	kinds := make(map[string]string)
+	for _, conflict := range report.Conflicts {
+		kinds[conflict.Keyword] = conflict.Kind
+	}
+	if kinds["新澳"] != AliasDuplicate || kinds["红"] != AliasNumberSet || len(report.Conflicts) != 2 {
+		t.Errorf("Conflicts = %+v", report.Conflicts)
+	}
+	// 按识别优先级排列，第一项生效
+	for _, conflict := range report.Conflicts {
+		if conflict.Keyword == "红" && conflict.Owners[0] != "下注类型\"二中二\"" {
+			t.Errorf("\"红\"的生效项 = %s, want 下注类型\"二中二\"", conflict.Owners[0])
+		}
+	}
+
+	shadowed := false
+	for _, conflict := range report.Shadowed {
+		if conflict.Keyword == "组" && conflict.Within == "每组" {
+			shadowed = true
+		}
+	}
+	if !shadowed {
+		t.Errorf("Shadowed = %+v, want \"组\"包含在\"每组\"中", report.Shadowed)
+	}
+
+	// 被包含的别名只提示，重复的别名拒绝保存
+	config.KeywordAliases.OldMacau = config.KeywordAliases.OldMacau[:len(config.KeywordAliases.OldMacau)-1]
+	config.BetTypeAliases.TwoOfTwo = config.BetTypeAliases.TwoOfTwo[:len(config.BetTypeAliases.TwoOfTwo)-1]
+	if err := checkAliasConflicts(config); err != nil {
+		t.Errorf("只有被包含的别名时不应拒绝: %v", err)
+	}
+}
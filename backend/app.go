@@ -3,6 +3,8 @@ package backend
 import (
 	"context"
 	"crypto/rsa"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -86,14 +88,24 @@ var pubKey *rsa.PublicKey
 type App struct {
 	ctx          context.Context
 	mutex        sync.RWMutex  // 读写锁
+	saveMutex    sync.Mutex    // 配置保存锁，从修改内存中的配置到写入配置文件期间持有，重新加载配置文件时同样持有
 	shutdownChan chan struct{} // 优雅关闭通道
 	shutdownOnce sync.Once     // 确保只关闭一次
 	authCode     string        // 授权码
 	authExpiry   time.Time     // 授权过期时间
+	operator     string        // 操作员，记录到配置审计日志中，为空时使用系统用户名
+
+	// configLoadErr 启动时配置文件不能加载的原因，此时暂用默认配置解析
+	// 配置文件修正后重新加载、从备份恢复或重置配置前禁止保存，避免默认配置覆盖原配置文件
+	configLoadErr error
 
 	// 六合彩相关数据
 	lotteryResults map[string]*LotteryResult // 开奖结果 (new_macau, old_macau, hongkong)
 	systemConfig   *SystemConfig             // 系统配置（内存缓存）
+
+	// 消息接入
+	ledger      BetLedger          // 下注账本
+	watchCancel context.CancelFunc // 停止目录监视，未监视时为nil
 }
 
 // NewApp 创建并返回一个新的 App 实例
@@ -128,15 +140,31 @@ func NewApp() *App {
 	// 加载系统配置到内存
 	systemConfig, configErr := loadSystemConfigFromFile()
 	if configErr != nil {
-		safeLogger.WriteLog(fmt.Sprintf("加载系统配置失败，使用默认配置: %v", configErr))
+		safeLogger.WriteLog(fmt.Sprintf("加载系统配置失败，暂用默认配置，配置文件修正前不保存配置: %v", configErr))
 		systemConfig = getDefaultSystemConfig()
 	}
+	// 手动修改过配置文件或首次运行时记录为新的配置版本
+	if _, err := configHistory.record(systemConfig); err != nil {
+		safeLogger.WriteLog(fmt.Sprintf("记录配置版本失败: %v", err))
+	}
+
+	// 读取账本文件，失败时暂用内存账本，本次运行的记录不保存
+	var ledger BetLedger = NewMemoryLedger()
+	if path, err := getLedgerFilePath(); err != nil {
+		safeLogger.WriteLog(fmt.Sprintf("获取账本文件路径失败，本次运行的账本记录不保存: %v", err))
+	} else if fileLedger, err := NewFileLedger(path); err != nil {
+		safeLogger.WriteLog(fmt.Sprintf("读取账本失败，本次运行的账本记录不保存: %v", err))
+	} else {
+		ledger = fileLedger
+	}
 
 	app := &App{
 		shutdownChan:   make(chan struct{}),
 		authExpiry:     time.Time{},
 		lotteryResults: make(map[string]*LotteryResult),
 		systemConfig:   systemConfig,
+		configLoadErr:  configErr,
+		ledger:         ledger,
 	}
 
 	safeLogger.WriteLog("六合彩智能解析机器人实例创建成功")
@@ -148,6 +176,9 @@ func (a *App) Startup(ctx context.Context) {
 	defer recoverWithLog("startup")
 	a.ctx = ctx
 	safeLogger.AppendLog("六合彩智能解析机器人已启动")
+
+	// 监视配置文件，被外部修改时自动重新加载
+	go a.watchConfigFile()
 	if ctx != nil {
 		wailsRuntime.LogInfo(ctx, "App has started up.")
 	}
@@ -314,84 +345,52 @@ func (a *App) GetSystemConfig() SystemConfig {
 	return *a.systemConfig
 }
 
-// GetZodiacConfig 获取生肖配置
-func (a *App) GetZodiacConfig() ZodiacConfig {
-	defer recoverWithLog("GetZodiacConfig")
-	a.mutex.RLock()
-	defer a.mutex.RUnlock()
-	return a.systemConfig.ZodiacConfig
-}
-
-// SaveZodiacConfig 保存生肖配置
-func (a *App) SaveZodiacConfig(config ZodiacConfig) error {
-	defer recoverWithLog("SaveZodiacConfig")
+// ValidateSystemConfig 校验系统配置，返回所有不合法的配置项，合法时返回空列表
+func (a *App) ValidateSystemConfig(config SystemConfig) []ConfigFieldError {
+	defer recoverWithLog("ValidateSystemConfig")
 
-	// 先更新内存中的配置
-	a.mutex.Lock()
-	a.systemConfig.ZodiacConfig = config
-	a.mutex.Unlock()
-
-	// 再保存到文件
-	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
-		safeLogger.AppendLog(fmt.Sprintf("保存生肖配置失败: %v", err))
-		return err
+	var validationErr *ConfigValidationError
+	if errors.As(validateSystemConfig(&config), &validationErr) {
+		return validationErr.Errors
 	}
-
-	safeLogger.AppendLog("生肖配置已更新")
-	return nil
+	return []ConfigFieldError{}
 }
 
-// GetColorConfig 获取颜色配置
-func (a *App) GetColorConfig() ColorConfig {
-	defer recoverWithLog("GetColorConfig")
+// GetNumberSets 获取号码集合
+func (a *App) GetNumberSets() []NumberSet {
+	defer recoverWithLog("GetNumberSets")
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
-	return a.systemConfig.ColorConfig
+	return a.systemConfig.NumberSets
 }
 
-// SaveColorConfig 保存颜色配置
-func (a *App) SaveColorConfig(config ColorConfig) error {
-	defer recoverWithLog("SaveColorConfig")
+// SaveNumberSets 保存号码集合
+func (a *App) SaveNumberSets(sets []NumberSet) error {
+	defer recoverWithLog("SaveNumberSets")
 
-	// 先更新内存中的配置
-	a.mutex.Lock()
-	a.systemConfig.ColorConfig = config
-	a.mutex.Unlock()
-
-	// 再保存到文件
-	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
-		safeLogger.AppendLog(fmt.Sprintf("保存颜色配置失败: %v", err))
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateNumberSets(sets); err != nil {
 		return err
 	}
-
-	safeLogger.AppendLog("颜色配置已更新")
-	return nil
-}
-
-// GetTailConfig 获取尾数配置
-func (a *App) GetTailConfig() TailConfig {
-	defer recoverWithLog("GetTailConfig")
-	a.mutex.RLock()
-	defer a.mutex.RUnlock()
-	return a.systemConfig.TailConfig
-}
-
-// SaveTailConfig 保存尾数配置
-func (a *App) SaveTailConfig(config TailConfig) error {
-	defer recoverWithLog("SaveTailConfig")
 
 	// 先更新内存中的配置
 	a.mutex.Lock()
-	a.systemConfig.TailConfig = config
+	before := cloneSystemConfig(a.systemConfig)
+	a.systemConfig.NumberSets = sets
 	a.mutex.Unlock()
 
 	// 再保存到文件
 	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
-		safeLogger.AppendLog(fmt.Sprintf("保存尾数配置失败: %v", err))
+		safeLogger.AppendLog(fmt.Sprintf("保存号码集合失败: %v", err))
 		return err
 	}
 
-	safeLogger.AppendLog("尾数配置已更新")
+	a.auditConfigChange("号码集合", &before)
+	safeLogger.AppendLog("号码集合已更新")
 	return nil
 }
 
@@ -407,8 +406,21 @@ func (a *App) GetBetTypeAliases() BetTypeAliases {
 func (a *App) SaveBetTypeAliases(config BetTypeAliases) error {
 	defer recoverWithLog("SaveBetTypeAliases")
 
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateBetTypeAliases(config); err != nil {
+		return err
+	}
+	if err := a.checkAliasChange(func(candidate *SystemConfig) { candidate.BetTypeAliases = config }); err != nil {
+		return err
+	}
+
 	// 先更新内存中的配置
 	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
 	a.systemConfig.BetTypeAliases = config
 	a.mutex.Unlock()
 
@@ -418,6 +430,7 @@ func (a *App) SaveBetTypeAliases(config BetTypeAliases) error {
 		return err
 	}
 
+	a.auditConfigChange("下注类型别名配置", &before)
 	safeLogger.AppendLog("下注类型别名配置已更新")
 	return nil
 }
@@ -434,8 +447,21 @@ func (a *App) GetKeywordAliases() KeywordAliases {
 func (a *App) SaveKeywordAliases(config KeywordAliases) error {
 	defer recoverWithLog("SaveKeywordAliases")
 
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateKeywordAliases(config); err != nil {
+		return err
+	}
+	if err := a.checkAliasChange(func(candidate *SystemConfig) { candidate.KeywordAliases = config }); err != nil {
+		return err
+	}
+
 	// 先更新内存中的配置
 	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
 	a.systemConfig.KeywordAliases = config
 	a.mutex.Unlock()
 
@@ -445,6 +471,7 @@ func (a *App) SaveKeywordAliases(config KeywordAliases) error {
 		return err
 	}
 
+	a.auditConfigChange("关键字别名配置", &before)
 	safeLogger.AppendLog("关键字别名配置已更新")
 	return nil
 }
@@ -461,8 +488,18 @@ func (a *App) GetOddsConfig() OddsConfig {
 func (a *App) SaveOddsConfig(config OddsConfig) error {
 	defer recoverWithLog("SaveOddsConfig")
 
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateOddsConfig(config); err != nil {
+		return err
+	}
+
 	// 先更新内存中的配置
 	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
 	a.systemConfig.OddsConfig = config
 	a.mutex.Unlock()
 
@@ -472,19 +509,203 @@ func (a *App) SaveOddsConfig(config OddsConfig) error {
 		return err
 	}
 
+	a.auditConfigChange("赔率配置", &before)
 	safeLogger.AppendLog("赔率配置已更新")
 	return nil
 }
 
+// GetZodiacSchedule 获取生肖换肖设置
+func (a *App) GetZodiacSchedule() ZodiacSchedule {
+	defer recoverWithLog("GetZodiacSchedule")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.systemConfig.ZodiacSchedule
+}
+
+// SaveZodiacSchedule 保存生肖换肖设置
+func (a *App) SaveZodiacSchedule(schedule ZodiacSchedule) error {
+	defer recoverWithLog("SaveZodiacSchedule")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateZodiacSchedule(schedule); err != nil {
+		return err
+	}
+
+	// 先更新内存中的配置
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	a.systemConfig.ZodiacSchedule = schedule
+	a.mutex.Unlock()
+
+	// 再保存到文件
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("保存换肖设置失败: %v", err))
+		return err
+	}
+
+	a.auditConfigChange("换肖设置", &before)
+	safeLogger.AppendLog("换肖设置已更新")
+	return nil
+}
+
+// GetZodiacTable 生成指定农历年份的生肖号码表，year为0时返回当前生效的号码表
+func (a *App) GetZodiacTable(year int) ZodiacTable {
+	defer recoverWithLog("GetZodiacTable")
+	if year == 0 {
+		return a.createParserConfig(time.Now()).ZodiacTable
+	}
+	return zodiacTableForYear(year)
+}
+
+// GetParserOptions 获取解析选项
+func (a *App) GetParserOptions() ParserOptions {
+	defer recoverWithLog("GetParserOptions")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.systemConfig.ParserOptions
+}
+
+// SaveParserOptions 保存解析选项
+func (a *App) SaveParserOptions(config ParserOptions) error {
+	defer recoverWithLog("SaveParserOptions")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateParserOptions(config); err != nil {
+		return err
+	}
+
+	// 先更新内存中的配置
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	a.systemConfig.ParserOptions = config
+	a.mutex.Unlock()
+
+	// 再保存到文件
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("保存解析选项失败: %v", err))
+		return err
+	}
+
+	a.auditConfigChange("解析选项", &before)
+	safeLogger.AppendLog("解析选项已更新")
+	return nil
+}
+
+// GetReplyTemplates 获取回复模板
+func (a *App) GetReplyTemplates() ReplyTemplates {
+	defer recoverWithLog("GetReplyTemplates")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.systemConfig.ReplyTemplates
+}
+
+// SaveReplyTemplates 保存回复模板
+func (a *App) SaveReplyTemplates(config ReplyTemplates) error {
+	defer recoverWithLog("SaveReplyTemplates")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	if err := validateReplyTemplates(config); err != nil {
+		return err
+	}
+
+	// 先更新内存中的配置
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	a.systemConfig.ReplyTemplates = config
+	a.mutex.Unlock()
+
+	// 再保存到文件
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("保存回复模板失败: %v", err))
+		return err
+	}
+
+	a.auditConfigChange("回复模板", &before)
+	safeLogger.AppendLog("回复模板已更新")
+	return nil
+}
+
+// RenderReply 按回复模板生成一轮下注的回复文本
+func (a *App) RenderReply(request ReplyRenderRequest) (string, error) {
+	defer recoverWithLog("RenderReply")
+
+	// 默认使用解析该轮下注时的配置版本中的回复模板，版本不存在时使用当前模板
+	a.mutex.RLock()
+	templates := a.systemConfig.ReplyTemplates
+	a.mutex.RUnlock()
+	if version := request.Result.ConfigVersion; version > 0 {
+		if historical, err := configHistory.get(version); err == nil {
+			templates = historical.Config.ReplyTemplates
+		} else {
+			safeLogger.AppendLog(fmt.Sprintf("%v，使用当前回复模板", err))
+		}
+	}
+
+	return renderReply(templates, request.Kind, request.Result, request.Payout)
+}
+
+// AnalyzeAliases 分析配置中各配置方案的别名冲突：重复的别名、与号码集合相同的别名及被较长别名包含的别名
+func (a *App) AnalyzeAliases(config SystemConfig) AliasReport {
+	defer recoverWithLog("AnalyzeAliases")
+	return analyzeAliases(&config)
+}
+
+// checkAliasChange 在当前配置的副本上应用修改后检查别名冲突
+func (a *App) checkAliasChange(change func(candidate *SystemConfig)) error {
+	a.mutex.RLock()
+	candidate := cloneSystemConfig(a.systemConfig)
+	a.mutex.RUnlock()
+
+	change(&candidate)
+	return checkAliasConflicts(&candidate)
+}
+
+// checkConfigWritable 检查是否可以保存配置：启动时配置文件不能加载的，须先修正配置文件、从备份恢复或重置配置
+func (a *App) checkConfigWritable() error {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.configLoadErr != nil {
+		return fmt.Errorf("配置文件不能加载，当前暂用默认配置，请先修正配置文件、从备份恢复或重置配置后再修改: %v", a.configLoadErr)
+	}
+	return nil
+}
+
+// GetConfigLoadError 获取启动时配置文件不能加载的原因，配置已正常加载时为空
+func (a *App) GetConfigLoadError() string {
+	defer recoverWithLog("GetConfigLoadError")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.configLoadErr == nil {
+		return ""
+	}
+	return a.configLoadErr.Error()
+}
+
 // ResetSystemConfig 重置系统配置
+// 启动时配置文件不能加载的，重置后以默认配置覆盖原配置文件（原文件在保存时自动备份）
 func (a *App) ResetSystemConfig() error {
 	defer recoverWithLog("ResetSystemConfig")
 
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
 	// 获取默认配置
 	defaultConfig := getDefaultSystemConfig()
 
 	// 先更新内存中的配置
 	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
 	a.systemConfig = defaultConfig
 	a.mutex.Unlock()
 
@@ -492,11 +713,131 @@ func (a *App) ResetSystemConfig() error {
 	if err := saveSystemConfigToFile(defaultConfig); err != nil {
 		return err
 	}
+	a.clearConfigLoadError()
 
+	a.auditConfigChange("重置系统配置", &before)
 	safeLogger.AppendLog("系统配置已重置为默认值")
 	return nil
 }
 
+// clearConfigLoadError 配置文件已替换为可加载的配置后允许保存
+func (a *App) clearConfigLoadError() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.configLoadErr = nil
+}
+
+// ================================
+// 配置方案相关方法
+// ================================
+
+// GetProfiles 获取所有配置方案，当前方案在前
+func (a *App) GetProfiles() []ProfileInfo {
+	defer recoverWithLog("GetProfiles")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return profileNames(a.systemConfig)
+}
+
+// GetProfileConfig 获取按配置方案生效的配置，profile为空时为当前方案，用于按该方案的赔率结算
+func (a *App) GetProfileConfig(profile string) (*SystemConfig, error) {
+	defer recoverWithLog("GetProfileConfig")
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	config, err := profileConfig(a.systemConfig, profile)
+	if err != nil {
+		return nil, err
+	}
+	result := cloneSystemConfig(config)
+	return &result, nil
+}
+
+// CreateProfile 按默认配置新建配置方案
+func (a *App) CreateProfile(name string) error {
+	defer recoverWithLog("CreateProfile")
+
+	if err := a.updateProfiles("新建配置方案", func(config *SystemConfig) error {
+		return createProfile(config, name)
+	}); err != nil {
+		return err
+	}
+	safeLogger.AppendLog("已新建配置方案: " + name)
+	return nil
+}
+
+// CloneProfile 复制配置方案，source为被复制的方案名称
+func (a *App) CloneProfile(source string, name string) error {
+	defer recoverWithLog("CloneProfile")
+
+	if err := a.updateProfiles("复制配置方案", func(config *SystemConfig) error {
+		return copyProfile(config, source, name)
+	}); err != nil {
+		return err
+	}
+	safeLogger.AppendLog(fmt.Sprintf("已复制配置方案: %s -> %s", source, name))
+	return nil
+}
+
+// SwitchProfile 切换当前配置方案，之后的解析默认使用该方案
+func (a *App) SwitchProfile(name string) error {
+	defer recoverWithLog("SwitchProfile")
+
+	if err := a.updateProfiles("切换配置方案", func(config *SystemConfig) error {
+		return switchProfile(config, name)
+	}); err != nil {
+		return err
+	}
+	safeLogger.AppendLog("已切换配置方案: " + name)
+	return nil
+}
+
+// DeleteProfile 删除配置方案，不能删除当前方案
+func (a *App) DeleteProfile(name string) error {
+	defer recoverWithLog("DeleteProfile")
+
+	if err := a.updateProfiles("删除配置方案", func(config *SystemConfig) error {
+		return deleteProfile(config, name)
+	}); err != nil {
+		return err
+	}
+	safeLogger.AppendLog("已删除配置方案: " + name)
+	return nil
+}
+
+// updateProfiles 在当前配置的副本上修改配置方案，校验通过且各方案没有别名冲突后替换当前配置并保存，section为审计日志中的修改说明
+func (a *App) updateProfiles(section string, change func(config *SystemConfig) error) error {
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	candidate := cloneSystemConfig(a.systemConfig)
+	if err := change(&candidate); err != nil {
+		a.mutex.Unlock()
+		return err
+	}
+	if err := validateSystemConfig(&candidate); err != nil {
+		a.mutex.Unlock()
+		return err
+	}
+	if err := checkAliasConflicts(&candidate); err != nil {
+		a.mutex.Unlock()
+		return err
+	}
+	*a.systemConfig = candidate
+	a.mutex.Unlock()
+
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("保存配置方案失败: %v", err))
+		return err
+	}
+	a.auditConfigChange(section, &before)
+	return nil
+}
+
 // ================================
 // 智能解析器API方法
 // ================================
@@ -505,6 +846,27 @@ func (a *App) ResetSystemConfig() error {
 func (a *App) ParseBetInputIntelligent(input string, enabledTypes []string) (*BetParsingResult, error) {
 	defer recoverWithLog("ParseBetInputIntelligent")
 
+	return a.parseBetInputIntelligent(input, enabledTypes, nil, "")
+}
+
+// ParseBetInputIntelligentWithChoices 按操作员在纠错窗口中选择的理解方式重新解析
+// choices key: 下注序号（从1开始） value: 理解方式下标
+func (a *App) ParseBetInputIntelligentWithChoices(input string, enabledTypes []string, choices map[int]int) (*BetParsingResult, error) {
+	defer recoverWithLog("ParseBetInputIntelligentWithChoices")
+
+	return a.parseBetInputIntelligent(input, enabledTypes, choices, "")
+}
+
+// ParseBetInputWithProfile 按指定配置方案解析下注输入，profile为空时使用当前方案
+// choices为操作员在纠错窗口中选择的理解方式
+func (a *App) ParseBetInputWithProfile(input string, enabledTypes []string, choices map[int]int, profile string) (*BetParsingResult, error) {
+	defer recoverWithLog("ParseBetInputWithProfile")
+
+	return a.parseBetInputIntelligent(input, enabledTypes, choices, profile)
+}
+
+// parseBetInputIntelligent 按配置方案执行智能解析，profile为空时使用当前方案
+func (a *App) parseBetInputIntelligent(input string, enabledTypes []string, choices map[int]int, profile string) (*BetParsingResult, error) {
 	if strings.TrimSpace(input) == "" {
 		result := &BetParsingResult{
 			HasError:      true,
@@ -515,16 +877,20 @@ func (a *App) ParseBetInputIntelligent(input string, enabledTypes []string) (*Be
 	}
 
 	// 创建解析器配置
-	config := a.createParserConfig()
+	config, err := a.createProfileParserConfig(profile)
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建智能解析器
 	parser := NewIntelligentBetParser(config)
 
 	// 构建解析请求
 	request := BetParseRequest{
-		Input:        input,
-		EnabledTypes: enabledTypes,
-		UserSettings: make(map[string]interface{}),
+		Input:                 input,
+		EnabledTypes:          enabledTypes,
+		UserSettings:          make(map[string]interface{}),
+		InterpretationChoices: choices,
 	}
 
 	// 执行智能解析
@@ -541,31 +907,465 @@ func (a *App) ParseBetInputIntelligent(input string, enabledTypes []string) (*Be
 	return &result, nil
 }
 
-// createParserConfig 创建解析器配置
-func (a *App) createParserConfig() IntelligentBetParserConfig {
+// ImportChatLog 导入导出的群聊记录，按期数、玩家分组解析下注
+func (a *App) ImportChatLog(content string, options ChatImportOptions) (*ChatImportResult, error) {
+	defer recoverWithLog("ImportChatLog")
+
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("聊天记录为空")
+	}
+
+	// 配置方案在导入开始时确定，各条消息按发送时间生成生肖号码
+	a.mutex.RLock()
+	config, err := profileConfig(a.systemConfig, options.Profile)
+	if err == nil {
+		snapshot := cloneSystemConfig(config)
+		config = &snapshot
+	}
+	a.mutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	version := configHistory.latest()
+
+	result, err := importChatLog(content, options, func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(config, version, at))
+	})
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("导入聊天记录失败: %v", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ================================
+// 消息接入相关方法
+// ================================
+
+// ledgerRecordedEvent 下注记入账本时发给前端的事件
+const ledgerRecordedEvent = "ledger:recorded"
+
+// StartMessageWatch 开始监视目录，放入的聊天记录文件自动解析并记入账本
+func (a *App) StartMessageWatch(dir string) error {
+	defer recoverWithLog("StartMessageWatch")
+
+	if strings.TrimSpace(dir) == "" {
+		return errors.New("监视目录为空")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("监视目录不存在: %s", dir)
+	}
+
+	a.mutex.Lock()
+	if a.watchCancel != nil {
+		a.mutex.Unlock()
+		return errors.New("已在监视目录，请先停止")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.watchCancel = cancel
+	a.mutex.Unlock()
+
+	dispatcher := NewMessageDispatcher(func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(a.createParserConfig(at))
+	}, a.ledger)
+	dispatcher.OnRecord = func(entry LedgerEntry) {
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx, ledgerRecordedEvent, entry)
+		}
+	}
+
+	go func() {
+		defer recoverWithLog("MessageWatch")
+		go func() {
+			select {
+			case <-a.shutdownChan:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		if err := dispatcher.Run(ctx, NewDirectorySource(dir)); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("目录监视失败: %v", err))
+		}
+
+		// 监视异常结束时清除状态，之后可以重新开始；已停止监视时ctx已取消，由StopMessageWatch清除
+		a.mutex.Lock()
+		if ctx.Err() == nil {
+			a.watchCancel = nil
+		}
+		a.mutex.Unlock()
+		cancel()
+	}()
+
+	safeLogger.AppendLog("开始监视目录: " + dir)
+	return nil
+}
+
+// StopMessageWatch 停止监视目录
+func (a *App) StopMessageWatch() {
+	defer recoverWithLog("StopMessageWatch")
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.watchCancel != nil {
+		a.watchCancel()
+		a.watchCancel = nil
+		safeLogger.AppendLog("已停止监视目录")
+	}
+}
+
+// GetLedgerEntries 获取账本中的所有记录
+func (a *App) GetLedgerEntries() []LedgerEntry {
+	defer recoverWithLog("GetLedgerEntries")
+	return a.ledger.Entries()
+}
+
+// ReparseLedgerEntry 重新解析账本中的一条记录
+// version为0时使用该记录解析时的配置版本，choices为操作员在纠错窗口中选择的理解方式
+func (a *App) ReparseLedgerEntry(id string, version int, choices map[int]int) (*BetParsingResult, error) {
+	defer recoverWithLog("ReparseLedgerEntry")
+
+	for _, entry := range a.ledger.Entries() {
+		if entry.ID != id {
+			continue
+		}
+		// 未指定配置版本时按记录时的配置版本及生肖号码表重新解析
+		var zodiacTable *ZodiacTable
+		if version == 0 {
+			version = entry.Result.ConfigVersion
+			if len(entry.Result.ZodiacTable.Numbers) > 0 {
+				zodiacTable = &entry.Result.ZodiacTable
+			}
+		}
+		if version == 0 {
+			version = configHistory.versionAt(entry.RecordedAt)
+		}
+		at := entry.Message.Time
+		if at.IsZero() {
+			at = entry.RecordedAt
+		}
+		return a.parseBetInputWithConfigVersion(entry.Message.Text, nil, choices, version, entry.Result.Profile, at, zodiacTable)
+	}
+	return nil, fmt.Errorf("账本记录不存在: %s", id)
+}
+
+// ================================
+// 配置版本相关方法
+// ================================
+
+// GetConfigVersions 获取所有配置版本概要
+func (a *App) GetConfigVersions() []ConfigVersionInfo {
+	defer recoverWithLog("GetConfigVersions")
+	return configHistory.list()
+}
+
+// GetConfigVersion 获取指定版本的配置，用于按当时的赔率、回复模板结算旧的轮次
+func (a *App) GetConfigVersion(version int) (*SystemConfig, error) {
+	defer recoverWithLog("GetConfigVersion")
+
+	historical, err := configHistory.get(version)
+	if err != nil {
+		return nil, err
+	}
+	return &historical.Config, nil
+}
+
+// SetOperator 设置当前操作员，之后的配置修改记录该操作员
+func (a *App) SetOperator(name string) {
+	defer recoverWithLog("SetOperator")
+	a.mutex.Lock()
+	a.operator = strings.TrimSpace(name)
+	a.mutex.Unlock()
+}
+
+// GetConfigAuditLog 获取配置修改记录，按时间从新到旧排列
+func (a *App) GetConfigAuditLog() []ConfigAuditEntry {
+	defer recoverWithLog("GetConfigAuditLog")
+	return configAudit.list()
+}
+
+// RevertConfigChange 撤销一次配置修改，将该次修改的配置项恢复为修改前的值
+// 这些配置项之后又被修改过时不撤销；撤销本身也记录为一次修改
+func (a *App) RevertConfigChange(id int) error {
+	defer recoverWithLog("RevertConfigChange")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	entry, err := configAudit.get(id)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	reverted, err := revertConfigChanges(a.systemConfig, entry.Changes)
+	if err == nil {
+		err = validateSystemConfig(reverted)
+	}
+	if err == nil {
+		err = checkAliasConflicts(reverted)
+	}
+	if err != nil {
+		a.mutex.Unlock()
+		return err
+	}
+	before := cloneSystemConfig(a.systemConfig)
+	*a.systemConfig = *reverted
+	a.mutex.Unlock()
+
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("撤销配置修改失败: %v", err))
+		return err
+	}
+
+	a.recordConfigAudit(entry.Section, "", &before, id)
+	safeLogger.AppendLog(fmt.Sprintf("已撤销配置修改#%d(%s)", id, entry.Section))
+	return nil
+}
+
+// GetConfigBackups 获取配置备份列表，按时间从新到旧排列
+func (a *App) GetConfigBackups() []ConfigBackupInfo {
+	defer recoverWithLog("GetConfigBackups")
+
+	configPath, err := getConfigFilePath()
+	if err == nil {
+		var backups []ConfigBackupInfo
+		if backups, err = listConfigBackups(configPath); err == nil {
+			return backups
+		}
+	}
+	safeLogger.AppendLog(fmt.Sprintf("获取配置备份失败: %v", err))
+	return []ConfigBackupInfo{}
+}
+
+// RestoreConfigBackup 从备份恢复配置，name为GetConfigBackups返回的备份文件名
+// 旧版本的备份会先升级到当前版本，校验通过且没有别名冲突后才替换当前配置，当前配置文件在保存时自动备份
+func (a *App) RestoreConfigBackup(name string) error {
+	defer recoverWithLog("RestoreConfigBackup")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := readConfigBackup(configPath, name)
+	if err != nil {
+		return err
+	}
+	config, _, err := decodeSystemConfig(data)
+	if err != nil {
+		return fmt.Errorf("配置备份无法使用: %v", err)
+	}
+	if err := validateSystemConfig(config); err != nil {
+		return fmt.Errorf("配置备份内容不合法: %v", err)
+	}
+	if err := checkAliasConflicts(config); err != nil {
+		return fmt.Errorf("配置备份无法使用: %v", err)
+	}
+
+	// 先更新内存中的配置
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	*a.systemConfig = *config
+	a.mutex.Unlock()
+
+	// 再保存到文件
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("恢复配置备份失败: %v", err))
+		return err
+	}
+	a.clearConfigLoadError()
+
+	a.auditConfigChange("从备份恢复配置", &before)
+	safeLogger.AppendLog("已从备份恢复配置: " + name)
+	return nil
+}
+
+// ExportConfigBundle 导出配置包（JSON文本），用于在多台机器之间同步配置
+// sections为配置项（如"odds_config"）或分组（"aliases"别名、"odds"赔率），为空时导出全部配置
+func (a *App) ExportConfigBundle(sections []string) (string, error) {
+	defer recoverWithLog("ExportConfigBundle")
+
+	a.mutex.RLock()
+	bundle, err := buildConfigBundle(a.systemConfig, sections)
+	a.mutex.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化配置包失败: %v", err)
+	}
+	safeLogger.AppendLog("已导出配置包: " + strings.Join(bundle.Sections, "、"))
+	return string(data), nil
+}
+
+// PreviewConfigImport 预览导入配置包后的变化，不修改当前配置
+// mode为"merge"合并或"replace"替换，预览中有校验错误或别名冲突时不能导入
+func (a *App) PreviewConfigImport(bundle string, mode string) (*ConfigImportPreview, error) {
+	defer recoverWithLog("PreviewConfigImport")
+
+	parsed, err := parseConfigBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
 	a.mutex.RLock()
 	defer a.mutex.RUnlock()
+	preview, _, err := previewConfigImport(a.systemConfig, parsed, mode)
+	return preview, err
+}
 
-	zodiacConfig := a.systemConfig.ZodiacConfig
-	colorConfig := a.systemConfig.ColorConfig
-	tailConfig := a.systemConfig.TailConfig
-	betTypeAliases := a.systemConfig.BetTypeAliases
-	keywordAliases := a.systemConfig.KeywordAliases
+// ImportConfigBundle 导入配置包，mode为"merge"合并或"replace"替换
+// 导入后的配置校验通过且没有别名冲突才保存，当前配置文件在保存时自动备份
+func (a *App) ImportConfigBundle(bundle string, mode string) error {
+	defer recoverWithLog("ImportConfigBundle")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+	if err := a.checkConfigWritable(); err != nil {
+		return err
+	}
+	parsed, err := parseConfigBundle(bundle)
+	if err != nil {
+		return err
+	}
+	a.mutex.RLock()
+	preview, config, err := previewConfigImport(a.systemConfig, parsed, mode)
+	a.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	if len(preview.Errors) > 0 {
+		return &ConfigValidationError{Errors: preview.Errors}
+	}
+	if err := checkAliasConflicts(config); err != nil {
+		return err
+	}
+	if len(preview.Changes) == 0 {
+		safeLogger.AppendLog("配置包与当前配置相同，无需导入")
+		return nil
+	}
+
+	// 先更新内存中的配置
+	a.mutex.Lock()
+	before := cloneSystemConfig(a.systemConfig)
+	*a.systemConfig = *config
+	a.mutex.Unlock()
+
+	// 再保存到文件
+	if err := saveSystemConfigToFile(a.systemConfig); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("导入配置包失败: %v", err))
+		return err
+	}
+
+	a.auditConfigChange("导入配置包", &before)
+	safeLogger.AppendLog(fmt.Sprintf("已导入配置包(%s): %d项配置变化", mode, len(preview.Changes)))
+	return nil
+}
+
+// ParseBetInputWithConfigVersion 按指定配置版本重新解析一轮下注，version为0时使用当前配置
+// 纠错后重新解析应传入原解析结果的配置版本及生肖号码表，使结果与原轮次一致（跨农历新年时生肖号码不变）
+// zodiacTable未记录号码时按当前时间生成生肖号码
+func (a *App) ParseBetInputWithConfigVersion(input string, enabledTypes []string, choices map[int]int, version int, zodiacTable ZodiacTable) (*BetParsingResult, error) {
+	defer recoverWithLog("ParseBetInputWithConfigVersion")
+
+	var recorded *ZodiacTable
+	if len(zodiacTable.Numbers) > 0 {
+		recorded = &zodiacTable
+	}
+	return a.parseBetInputWithConfigVersion(input, enabledTypes, choices, version, "", time.Now(), recorded)
+}
+
+// parseBetInputWithConfigVersion 按指定配置版本及配置方案执行智能解析，at为下注时间
+// profile为空时使用该版本的当前方案；zodiacTable不为nil时使用原轮次记录的生肖号码表，不再按下注时间生成
+func (a *App) parseBetInputWithConfigVersion(input string, enabledTypes []string, choices map[int]int, version int, profile string, at time.Time, zodiacTable *ZodiacTable) (*BetParsingResult, error) {
+	if version == 0 && zodiacTable == nil {
+		return a.parseBetInputIntelligent(input, enabledTypes, choices, profile)
+	}
+
+	var parserConfig IntelligentBetParserConfig
+	if version == 0 {
+		var err error
+		if parserConfig, err = a.createProfileParserConfig(profile); err != nil {
+			return nil, err
+		}
+	} else {
+		historical, err := configHistory.get(version)
+		if err != nil {
+			return nil, err
+		}
+		config, err := profileConfig(&historical.Config, profile)
+		if err != nil {
+			return nil, fmt.Errorf("配置版本%d中%v", version, err)
+		}
+		parserConfig = newParserConfig(config, version, at)
+	}
+	if zodiacTable != nil {
+		parserConfig = withZodiacTable(parserConfig, *zodiacTable)
+	}
+
+	parser := NewIntelligentBetParser(parserConfig)
+	result := parser.ParseBetString(BetParseRequest{
+		Input:                 input,
+		EnabledTypes:          enabledTypes,
+		UserSettings:          make(map[string]interface{}),
+		InterpretationChoices: choices,
+	})
+	safeLogger.AppendLog(fmt.Sprintf("按配置版本%d重新解析: %d笔下注, 总金额%s元",
+		parserConfig.ConfigVersion, result.RoundStatistics.TotalBets, result.RoundStatistics.TotalAmount.String()))
+	return &result, nil
+}
+
+// createParserConfig 按当前配置创建解析器配置，at为下注时间，自动换肖时按该时间生成生肖号码
+func (a *App) createParserConfig(at time.Time) IntelligentBetParserConfig {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return newParserConfig(a.systemConfig, configHistory.latest(), at)
+}
+
+// createProfileParserConfig 按配置方案创建解析器配置，profile为空时使用当前方案
+func (a *App) createProfileParserConfig(profile string) (IntelligentBetParserConfig, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	config, err := profileConfig(a.systemConfig, profile)
+	if err != nil {
+		return IntelligentBetParserConfig{}, err
+	}
+	return newParserConfig(config, configHistory.latest(), time.Now()), nil
+}
+
+// withZodiacTable 使用指定的生肖号码表替换解析器配置中的生肖号码
+func withZodiacTable(config IntelligentBetParserConfig, table ZodiacTable) IntelligentBetParserConfig {
+	config.ZodiacTable = table
+	config.NumberSets = applyZodiacTable(config.NumberSets, table)
+	return config
+}
+
+// newParserConfig 由系统配置创建解析器配置
+// version为配置版本，记录到解析结果中；at为下注时间，自动换肖时按该时间所在的农历年份生成生肖号码
+func newParserConfig(config *SystemConfig, version int, at time.Time) IntelligentBetParserConfig {
+	betTypeAliases := config.BetTypeAliases
+	keywordAliases := config.KeywordAliases
+	parserOptions := config.ParserOptions
+
+	// 自动换肖时按农历年份生成生肖号码，否则使用号码集合中配置的生肖号码
+	numberSets := config.NumberSets
+	zodiacTable := zodiacTableFromNumberSets(numberSets)
+	if schedule := config.ZodiacSchedule; schedule.AutoUpdate {
+		zodiacTable = zodiacTableForYear(lunarYearAt(at, schedule.NewYearDates))
+		numberSets = applyZodiacTable(numberSets, zodiacTable)
+	}
 
 	return IntelligentBetParserConfig{
-		ZodiacMap: map[string][]int{
-			"鼠": zodiacConfig.Rat, "牛": zodiacConfig.Ox, "虎": zodiacConfig.Tiger, "兔": zodiacConfig.Rabbit,
-			"龙": zodiacConfig.Dragon, "蛇": zodiacConfig.Snake, "马": zodiacConfig.Horse, "羊": zodiacConfig.Goat,
-			"猴": zodiacConfig.Monkey, "鸡": zodiacConfig.Rooster, "狗": zodiacConfig.Dog, "猪": zodiacConfig.Pig,
-		},
-		ColorMap: map[string][]int{
-			"红": colorConfig.Red, "蓝": colorConfig.Blue, "绿": colorConfig.Green,
-		},
-		TailMap: map[string][]int{
-			"0尾": tailConfig.Tail0, "1尾": tailConfig.Tail1, "2尾": tailConfig.Tail2, "3尾": tailConfig.Tail3,
-			"4尾": tailConfig.Tail4, "5尾": tailConfig.Tail5, "6尾": tailConfig.Tail6, "7尾": tailConfig.Tail7,
-			"8尾": tailConfig.Tail8, "9尾": tailConfig.Tail9,
-		},
+		NumberSets:    numberSets,
+		ZodiacTable:   zodiacTable,
+		ConfigVersion: version,
+		Profile:       config.ActiveProfile,
 		BetTypeAliases: map[string][]string{
 			"三中三": betTypeAliases.ThreeOfThree,
 			"三中二": betTypeAliases.ThreeOfTwo,
@@ -585,5 +1385,8 @@ func (a *App) createParserConfig() IntelligentBetParserConfig {
 			"各":  keywordAliases.Each,
 			"每组": keywordAliases.PerGroup,
 		},
+		StrictUnknownText:    parserOptions.StrictUnknownText,
+		WeChatFilter:         parserOptions.WeChatFilter,
+		PlayerHeaderPatterns: parserOptions.PlayerHeaderPatterns,
 	}
 }
@@ -0,0 +1,31 @@
+package backend
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWithZodiacTable(t *testing.T) {
+	// 原轮次在2026年农历新年前解析，纠错时已过新年，仍按原轮次的生肖号码表解析
+	recorded := zodiacTableForYear(2025)
+	at := time.Date(2026, 2, 20, 12, 0, 0, 0, time.Local)
+	config := withZodiacTable(newParserConfig(getDefaultSystemConfig(), 0, at), recorded)
+
+	if config.ZodiacTable.LunarYear != 2025 {
+		t.Fatalf("生肖号码表年份 = %d, want 2025", config.ZodiacTable.LunarYear)
+	}
+	for _, set := range config.NumberSets {
+		if set.Category != NumberSetZodiac {
+			continue
+		}
+		if want := recorded.Numbers[set.Name]; !slices.Equal(set.Numbers, want) {
+			t.Errorf("%s = %v, want %v", set.Name, set.Numbers, want)
+		}
+	}
+
+	result := NewIntelligentBetParser(config).ParseBetString(BetParseRequest{Input: "三中三1.2.3各5"})
+	if result.ZodiacTable.LunarYear != 2025 {
+		t.Errorf("解析结果记录的生肖号码表年份 = %d, want 2025", result.ZodiacTable.LunarYear)
+	}
+}
@@ -0,0 +1,316 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 各理解方式的基础权重，最终置信度为同一笔下注内各可行理解的权重归一化结果
+const (
+	weightAsIs         = 0.6  // 按原文解析
+	weightExplicitMode = 0.9  // 原文明确写了"复式"
+	weightAmountAtEnd  = 0.5  // 末位数字视为金额
+	weightExactCount   = 0.2  // 去掉末位后号码个数正好符合玩法，额外加权
+	weightInferredType = 0.5  // 按号码个数推断下注类型
+	weightMergeComplex = 0.4  // 多组合并为复式
+	weightSplitGroups  = 0.45 // 单组按玩法个数切分为多组
+)
+
+// fragmentShape 单笔下注文本的结构概要，用于生成其他理解方式
+type fragmentShape struct {
+	lotteries  []string // 出现的体彩
+	betTypes   []string // 出现的下注类型
+	groups     [][]int  // 号码组（不含拖码、金额）
+	groupExprs []string // 各号码组的号码集合表达式原文（如"鼠牛"、"红波去12"），与groups对应，不含号码集合时为空
+	amountText string   // 金额表达式，如"各5"
+	hasDrag    bool     // 是否为拖码
+	hasComplex bool     // 是否明确写了"复式"
+}
+
+// detectAmbiguity 检测单笔下注的歧义，返回各可行理解方式及置信度
+// 只有存在两种以上可行理解，或按原文无法解析但存在可行理解时才返回结果
+// 按原文无法解析时不替操作员选择理解（Selected为-1），即使只剩一种可行理解
+// tokens为单笔下注的词法单元，号码集合按词法单元识别，不会因展开为号码而被当作直接写出的号码
+func (p *IntelligentBetParser) detectAmbiguity(tokens []Token, context *BetContext) *BetAmbiguity {
+	text := renderTokens(tokens)
+	shape := p.describeFragment(tokens)
+	if shape.hasDrag {
+		// 拖码格式明确，不做其他理解
+		return nil
+	}
+
+	candidates := []BetInterpretation{{Label: "按原文解析", Text: text, Confidence: weightAsIs}}
+	if shape.hasComplex {
+		candidates[0].Confidence = weightExplicitMode
+	}
+	candidates = append(candidates, p.amountAtEndCandidates(shape)...)
+	candidates = append(candidates, p.missingAmountCandidates(shape)...)
+	candidates = append(candidates, p.betTypeCandidates(text, shape, context)...)
+	if !shape.hasComplex {
+		candidates = append(candidates, p.groupingCandidates(shape)...)
+	}
+
+	// 逐一试解析，只保留可行的理解
+	viable := make([]BetInterpretation, 0, len(candidates))
+	asIsViable := false
+	seen := make(map[string]bool)
+	for i, candidate := range candidates {
+		if seen[candidate.Text] {
+			continue
+		}
+		seen[candidate.Text] = true
+
+		trialText := candidate.Text
+		if candidate.NeedAmount {
+			// 缺少金额的理解按每组1元试解析，只统计组数
+			trialText += " 各1"
+		}
+		trial := p.parseSingleBet("trial", trialText, context.clone())
+		if trial.HasError || trial.BetStatistics.TotalGroups == 0 {
+			continue
+		}
+		if i == 0 {
+			asIsViable = true
+		}
+		candidate.TotalGroups = trial.BetStatistics.TotalGroups
+		if !candidate.NeedAmount {
+			candidate.TotalAmount = trial.BetStatistics.TotalAmount
+		}
+		viable = append(viable, candidate)
+	}
+
+	if len(viable) == 0 || (asIsViable && len(viable) == 1) {
+		return nil
+	}
+
+	// 置信度归一化并按置信度降序排列
+	totalWeight := 0.0
+	for _, candidate := range viable {
+		totalWeight += candidate.Confidence
+	}
+	for i := range viable {
+		viable[i].Confidence = math.Round(viable[i].Confidence/totalWeight*100) / 100
+	}
+	sort.SliceStable(viable, func(i, j int) bool {
+		return viable[i].Confidence > viable[j].Confidence
+	})
+
+	ambiguity := &BetAmbiguity{
+		OriginalText:    text,
+		Interpretations: viable,
+	}
+	if !asIsViable {
+		ambiguity.Selected = -1
+	}
+	return ambiguity
+}
+
+// amountAtEndCandidates 没有金额时，将末位数字理解为金额，如"12-38-20"理解为二中二12-38各20
+// 原文写明了下注类型时只按该下注类型理解，不改动玩家写的下注类型；没写时按剩余号码个数推断
+func (p *IntelligentBetParser) amountAtEndCandidates(shape fragmentShape) []BetInterpretation {
+	if shape.amountText != "" || len(shape.groups) != 1 || len(shape.groups[0]) < 3 {
+		return nil
+	}
+
+	group := shape.groups[0]
+	numbers := group[:len(group)-1]
+	amount := group[len(group)-1]
+	amountText := "各" + strconv.Itoa(amount)
+
+	candidates := make([]BetInterpretation, 0, 2)
+	addCandidate := func(betTypes []string, weight float64) {
+		candidates = append(candidates, BetInterpretation{
+			Label:      fmt.Sprintf("末位%d为金额: %s %s%s", amount, strings.Join(betTypes, ""), joinNumbers(numbers), amountText),
+			Text:       buildFragmentText(shape.lotteries, betTypes, false, [][]int{numbers}, amountText),
+			Confidence: weight,
+		})
+	}
+
+	if len(shape.betTypes) > 0 {
+		weight := weightAmountAtEnd
+		for _, betType := range shape.betTypes {
+			if betTypeNumberCount(betType) == len(numbers) {
+				weight += weightExactCount
+				break
+			}
+		}
+		addCandidate(shape.betTypes, weight)
+	} else if inferred := inferBetTypeByCount(len(numbers)); inferred != "" {
+		addCandidate([]string{inferred}, weightAmountAtEnd)
+	}
+	return candidates
+}
+
+// missingAmountCandidates 没有下注类型及金额时，按全部号码的个数推断下注类型，如"12-38-20"理解为三中三12-38-20
+// 该理解缺少金额，选择后须补充金额
+func (p *IntelligentBetParser) missingAmountCandidates(shape fragmentShape) []BetInterpretation {
+	if len(shape.betTypes) > 0 || shape.amountText != "" || len(shape.groups) != 1 {
+		return nil
+	}
+	group := shape.groups[0]
+	inferred := inferBetTypeByCount(len(group))
+	if inferred == "" {
+		return nil
+	}
+	return []BetInterpretation{{
+		Label:      fmt.Sprintf("按号码个数判断为%s: %s（未写金额）", inferred, joinNumbers(group)),
+		Text:       buildFragmentText(shape.lotteries, []string{inferred}, false, [][]int{group}, ""),
+		Confidence: weightInferredType,
+		NeedAmount: true,
+	}}
+}
+
+// betTypeCandidates 没有下注类型时按号码个数推断，与沿用的上一笔下注类型不同时才作为其他理解
+func (p *IntelligentBetParser) betTypeCandidates(text string, shape fragmentShape, context *BetContext) []BetInterpretation {
+	if len(shape.betTypes) > 0 || shape.amountText == "" || len(shape.groups) == 0 {
+		return nil
+	}
+
+	// 所有号码组个数相同时按个数推断
+	size := len(shape.groups[0])
+	for _, group := range shape.groups {
+		if len(group) != size {
+			return nil
+		}
+	}
+	inferred := inferBetTypeByCount(size)
+	if inferred == "" || (len(context.inheritedBetTypes) == 1 && context.inheritedBetTypes[0] == inferred) {
+		return nil
+	}
+	return []BetInterpretation{{
+		Label:      fmt.Sprintf("按号码个数判断为%s", inferred),
+		Text:       inferred + " " + text,
+		Confidence: weightInferredType,
+	}}
+}
+
+// groupingCandidates 复式与分组之间的歧义
+// 多组号码个数正好符合玩法时可理解为合并复式；单组号码可按玩法个数平均切分时可理解为多组
+// 号码集合展开的号码本身就是一组复式，不做合并或切分
+func (p *IntelligentBetParser) groupingCandidates(shape fragmentShape) []BetInterpretation {
+	if len(shape.betTypes) == 0 || shape.amountText == "" || len(shape.groups) == 0 || shape.hasSetGroup() {
+		return nil
+	}
+
+	// 多种下注类型所需个数不同时无法判断
+	n := betTypeNumberCount(shape.betTypes[0])
+	for _, betType := range shape.betTypes[1:] {
+		if betTypeNumberCount(betType) != n {
+			return nil
+		}
+	}
+
+	if len(shape.groups) >= 2 {
+		allNumbers := make([]int, 0)
+		for _, group := range shape.groups {
+			if len(group) != n {
+				return nil
+			}
+			allNumbers = append(allNumbers, group...)
+		}
+		allNumbers, _ = dedupeNumbers(allNumbers)
+		return []BetInterpretation{{
+			Label:      fmt.Sprintf("合并为复式: %s", joinNumbers(allNumbers)),
+			Text:       buildFragmentText(shape.lotteries, shape.betTypes, true, [][]int{allNumbers}, shape.amountText),
+			Confidence: weightMergeComplex,
+		}}
+	}
+
+	group := shape.groups[0]
+	if len(group) <= n || len(group)%n != 0 {
+		return nil
+	}
+	chunks := make([][]int, 0, len(group)/n)
+	for i := 0; i < len(group); i += n {
+		chunks = append(chunks, group[i:i+n])
+	}
+	return []BetInterpretation{{
+		Label:      fmt.Sprintf("每%d个号码为一组，共%d组", n, len(chunks)),
+		Text:       buildFragmentText(shape.lotteries, shape.betTypes, false, chunks, shape.amountText),
+		Confidence: weightSplitGroups,
+	}}
+}
+
+// describeFragment 提取单笔下注的结构概要
+func (p *IntelligentBetParser) describeFragment(tokens []Token) fragmentShape {
+	syntax := p.analyzeBetTokens(tokens)
+	shape := fragmentShape{
+		lotteries:  syntax.Lotteries,
+		betTypes:   syntax.orderedBetTypes(),
+		amountText: syntax.AmountText,
+		hasDrag:    syntax.HasDrag,
+		hasComplex: syntax.Complex,
+	}
+
+	for _, group := range syntax.Groups {
+		if group.Count < 2 {
+			continue
+		}
+		// 越界的数字可能是金额，交由末位金额理解处理，这里不做范围校验
+		numbers := make([]int, 0, group.Count)
+		for _, s := range strings.Split(group.Text, "-") {
+			if num, err := strconv.Atoi(s); err == nil {
+				numbers = append(numbers, num)
+			}
+		}
+		shape.groups = append(shape.groups, numbers)
+		shape.groupExprs = append(shape.groupExprs, group.Expression)
+	}
+
+	return shape
+}
+
+// hasSetGroup 是否有号码组由号码集合展开
+func (s fragmentShape) hasSetGroup() bool {
+	return slices.ContainsFunc(s.groupExprs, func(expr string) bool { return expr != "" })
+}
+
+// inferBetTypeByCount 按号码个数推断下注类型
+func inferBetTypeByCount(count int) string {
+	switch count {
+	case 2:
+		return "二中二"
+	case 3:
+		return "三中三"
+	}
+	return ""
+}
+
+// buildFragmentText 按规范格式拼接单笔下注文本
+func buildFragmentText(lotteries []string, betTypes []string, complex bool, groups [][]int, amountText string) string {
+	parts := make([]string, 0, len(lotteries)+len(groups)+2)
+	parts = append(parts, lotteries...)
+	prefix := strings.Join(betTypes, "")
+	if complex {
+		prefix = "复式" + prefix
+	}
+	parts = append(parts, prefix)
+	for _, group := range groups {
+		parts = append(parts, joinNumbers(group))
+	}
+	if amountText != "" {
+		parts = append(parts, amountText)
+	}
+	return strings.Join(parts, " ")
+}
+
+// joinNumbers 将号码以"-"连接，如[1,2,3] -> "01-02-03"
+func joinNumbers(numbers []int) string {
+	strs := make([]string, len(numbers))
+	for i, num := range numbers {
+		strs[i] = fmt.Sprintf("%02d", num)
+	}
+	return strings.Join(strs, "-")
+}
+
+// clone 复制上下文，用于试解析时不影响真实的继承状态
+func (c *BetContext) clone() *BetContext {
+	return &BetContext{
+		inheritedLotteries: append([]string(nil), c.inheritedLotteries...),
+		inheritedBetTypes:  append([]string(nil), c.inheritedBetTypes...),
+	}
+}
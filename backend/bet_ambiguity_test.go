@@ -0,0 +1,33 @@
+package backend
+
+import "testing"
+
+func TestDetectAmbiguitySetGroups(t *testing.T) {
+	tests := []struct {
+		input     string
+		ambiguous bool
+	}{
+		// 号码集合展开的号码为一组复式，不切分
+		{"二中二鼠牛各5", false},
+		{"二中二红单各1", false},
+		{"二中二 红波去12 各5", false},
+		{"二中二 鼠 牛 各5", false},
+		// 直接写出的号码个数可按玩法切分时仍有歧义
+		{"二中二1.2.3.4各5", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError {
+				t.Fatalf("解析失败: %v", result.ErrorMessages)
+			}
+			if got := len(result.Ambiguities) > 0; got != tt.ambiguous {
+				t.Errorf("有歧义 = %v, want %v: %+v", got, tt.ambiguous, result.Ambiguities)
+			}
+			if !tt.ambiguous && len(result.WarningMessages) > 0 {
+				t.Errorf("不应有歧义提示: %v", result.WarningMessages)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 声明关键词：玩家在下注后附带的组数、合计说明（如"27组"、"共540"、"合计300"）
+// 词法分析时统一识别为"组数27"、"合计540"
+const (
+	declaredGroupsKeyword = "组数"
+	declaredTotalKeyword  = "合计"
+)
+
+// BetDeclaration 单笔下注中玩家声明的组数与合计
+type BetDeclaration struct {
+	Groups int             // 声明的组数，0表示未声明
+	Total  decimal.Decimal // 声明的合计金额，零值表示未声明
+}
+
+// extractDeclarations 从下注中提取声明并移除，避免声明数字被当作下注号码
+func (p *IntelligentBetParser) extractDeclarations(tokens []Token) ([]Token, BetDeclaration) {
+	declaration := BetDeclaration{Total: decimal.Zero}
+	result := make([]Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		if token.Type != TokenDeclaration {
+			result = append(result, token)
+			continue
+		}
+		switch token.Value {
+		case declaredGroupsKeyword:
+			declaration.Groups = token.Number
+		case declaredTotalKeyword:
+			declaration.Total = decimal.NewFromInt(int64(token.Number))
+		}
+		// 以空白代替，避免声明前后的号码被连接
+		result = append(result, spaceToken)
+	}
+
+	return trimSeparators(result), declaration
+}
+
+// checkDeclaration 将声明与实际解析结果对比，不一致时在该笔下注上记录提示
+// roundTotal 为截至本笔（含）的整轮累计金额，玩家常在最后一笔后写整条消息的合计
+func (p *IntelligentBetParser) checkDeclaration(bet *SingleBetParsing, declaration BetDeclaration, roundTotal decimal.Decimal) {
+	bet.DeclaredGroups = declaration.Groups
+	bet.DeclaredTotal = declaration.Total
+
+	if bet.HasError {
+		return
+	}
+
+	if declaration.Groups > 0 && !p.matchesDeclaredGroups(bet, declaration.Groups) {
+		bet.Warnings = append(bet.Warnings,
+			fmt.Sprintf("声明%d组，实际解析%d组，请核对", declaration.Groups, bet.BetStatistics.TotalGroups))
+	}
+
+	if declaration.Total.IsPositive() &&
+		!declaration.Total.Equal(bet.BetStatistics.TotalAmount) &&
+		!declaration.Total.Equal(roundTotal) {
+		bet.Warnings = append(bet.Warnings,
+			fmt.Sprintf("声明合计%s元，实际解析%s元，请核对", declaration.Total.String(), bet.BetStatistics.TotalAmount.String()))
+	}
+}
+
+// matchesDeclaredGroups 声明组数与本笔总组数或任一模式的组数一致即视为匹配
+func (p *IntelligentBetParser) matchesDeclaredGroups(bet *SingleBetParsing, declaredGroups int) bool {
+	if bet.BetStatistics.TotalGroups == declaredGroups {
+		return true
+	}
+	for _, lotteryInfo := range bet.LotteryBets {
+		if lotteryInfo.TotalGroups == declaredGroups {
+			return true
+		}
+		for _, detail := range lotteryInfo.BetTypeDetails {
+			for _, mode := range detail.Modes {
+				if mode.Groups == declaredGroups {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckDeclaration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		groups   int    // 最后一笔下注声明的组数
+		total    string // 最后一笔下注声明的合计
+		warnings int    // 最后一笔下注的提示信息条数
+	}{
+		{"组数及合计一致", "三中三1.2.3.4各5 4组 共20", 4, "20", 0},
+		{"组数不一致", "三中三1.2.3.4各5 5组", 5, "0", 1},
+		{"合计不一致", "三中三1.2.3.4各5 合计30", 0, "30", 1},
+		{"合计带冒号及单位", "三中三1.2.3.4各5 共计：20元", 0, "20", 0},
+		{"共N组为组数声明", "二中二1.2.3各10 共27组", 27, "0", 1},
+		{"最后一笔的合计为整条消息的合计", "三中三1.2.3.4各5\n二中二1.2各5 合计25", 0, "25", 0},
+		// 声明的数字不作为号码
+		{"声明不作为号码", "二中二1.2.3各10 3组 合计30", 3, "30", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError {
+				t.Fatalf("解析失败: %v", result.ErrorMessages)
+			}
+			bet := result.ParsedBets[len(result.ParsedBets)-1]
+			if bet.DeclaredGroups != tt.groups || !bet.DeclaredTotal.Equal(decimal.RequireFromString(tt.total)) {
+				t.Errorf("声明 = %d组 合计%s, want %d组 合计%s", bet.DeclaredGroups, bet.DeclaredTotal, tt.groups, tt.total)
+			}
+			if len(bet.Warnings) != tt.warnings {
+				t.Errorf("提示信息 = %q, want %d条", bet.Warnings, tt.warnings)
+			}
+		})
+	}
+}
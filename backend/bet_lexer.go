@@ -0,0 +1,518 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenType 词法单元类型
+type TokenType int
+
+const (
+	TokenNumber      TokenType = iota // 号码（阿拉伯数字）
+	TokenSeparator                    // 分隔符（标点、空白等）
+	TokenLottery                      // 体彩（新澳、老澳、香港）
+	TokenBetType                      // 下注类型（三中三、三中二、二中二、特碰）
+	TokenMode                         // 模式（复式、拖）
+	TokenEndKeyword                   // 金额关键词（各、每组）
+	TokenAmount                       // 金额，紧跟在金额关键词之后
+	TokenNumberSet                    // 号码集合（生肖、波色、尾数等）
+	TokenDeclaration                  // 玩家声明的组数、合计（27组、共540）
+	TokenSetOperator                  // 号码集合运算（去、除、不要、除外），词法分析结束前与运算对象合并为号码集合
+	TokenUnknown                      // 无法识别的文字
+)
+
+// tokenTypeNames 词法单元类型名称，用于日志
+var tokenTypeNames = map[TokenType]string{
+	TokenNumber:      "NUMBER",
+	TokenSeparator:   "SEPARATOR",
+	TokenLottery:     "LOTTERY",
+	TokenBetType:     "BET_TYPE",
+	TokenMode:        "MODE",
+	TokenEndKeyword:  "END_KEYWORD",
+	TokenAmount:      "AMOUNT",
+	TokenNumberSet:   "NUMBER_SET",
+	TokenDeclaration: "DECLARATION",
+	TokenSetOperator: "SET_OPERATOR",
+	TokenUnknown:     "UNKNOWN",
+}
+
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TokenType(%d)", int(t))
+}
+
+// Token 词法单元
+type Token struct {
+	Type     TokenType // 类型
+	Text     string    // 原文
+	Value    string    // 规范值：关键词为标准名称（如"死"为"三中三"），数字、金额为阿拉伯数字
+	Number   int       // 金额、声明的数值
+	Numbers  []int     // 号码集合对应的号码
+	Category string    // 号码集合分类，相邻的不同分类号码集合取交集
+	Start    int       // 在原文中的起始位置（字节）
+	End      int       // 在原文中的结束位置（字节，不含）
+}
+
+// 号码之间的连接符：前后都是号码且连续使用同一种连接符时视为同一组号码
+var joinSeparators = map[rune]bool{
+	'.': true, '/': true, '\\': true, '-': true, '=': true, ':': true,
+	',': true, '，': true, '、': true, '+': true, '。': true, '*': true,
+}
+
+// 声明关键词：组数声明可写作"27组"、"共27组"，合计声明可写作"共540"、"合计：300元"
+var declarationKeywords = map[string]string{
+	declaredGroupsKeyword: declaredGroupsKeyword,
+	"合计":                  declaredTotalKeyword,
+	"总计":                  declaredTotalKeyword,
+	"共计":                  declaredTotalKeyword,
+	"总共":                  declaredTotalKeyword,
+	"共":                   declaredTotalKeyword,
+}
+
+// 中文数字，用于识别"各十"、"每组二十"等金额
+const chineseNumerals = "零一二三四五六七八九十百千万"
+
+// trieEntry 关键词对应的词法单元
+type trieEntry struct {
+	tokenType TokenType
+	value     string
+	numbers   []int
+	category  string
+}
+
+// trieNode 关键词前缀树节点
+type trieNode struct {
+	children map[rune]*trieNode
+	entry    *trieEntry
+}
+
+// keywordTrie 关键词前缀树，按最长匹配识别关键词
+type keywordTrie struct {
+	root *trieNode
+}
+
+// newKeywordTrie 根据解析器配置构建关键词前缀树
+// 同一别名配置在多处时以先加入的为准：声明 > 下注类型 > 体彩 > 模式 > 金额关键词 > 号码集合 > 集合运算
+func newKeywordTrie(config IntelligentBetParserConfig) *keywordTrie {
+	trie := &keywordTrie{root: &trieNode{children: make(map[rune]*trieNode)}}
+
+	for _, keyword := range sortedKeys(declarationKeywords) {
+		trie.insert(keyword, trieEntry{tokenType: TokenDeclaration, value: declarationKeywords[keyword]})
+	}
+	trie.insertAliases(TokenBetType, config.BetTypeAliases)
+	trie.insertAliases(TokenLottery, config.LotteryAliases)
+	trie.insertAliases(TokenMode, config.KeywordAliases)
+	trie.insertAliases(TokenEndKeyword, config.EndKeywords)
+	for _, set := range config.NumberSets {
+		for _, keyword := range append([]string{set.Name}, set.Aliases...) {
+			trie.insert(keyword, trieEntry{tokenType: TokenNumberSet, value: set.Name, numbers: set.Numbers, category: set.Category})
+		}
+	}
+	for _, keyword := range sortedKeys(setOperatorKeywords) {
+		trie.insert(keyword, trieEntry{tokenType: TokenSetOperator, value: setOperatorKeywords[keyword]})
+	}
+
+	return trie
+}
+
+// insertAliases 加入标准名称及其所有别名
+func (t *keywordTrie) insertAliases(tokenType TokenType, aliases map[string][]string) {
+	for _, name := range sortedKeys(aliases) {
+		t.insert(name, trieEntry{tokenType: tokenType, value: name})
+		for _, alias := range aliases[name] {
+			t.insert(alias, trieEntry{tokenType: tokenType, value: name})
+		}
+	}
+}
+
+// insert 加入关键词，已存在的关键词不会被覆盖
+// 关键词与输入文本按同样的规则规范化，配置为繁体或全角的别名同样可以匹配
+func (t *keywordTrie) insert(keyword string, entry trieEntry) {
+	keyword = normalizeText(keyword)
+	if keyword == "" {
+		return
+	}
+	node := t.root
+	for _, r := range keyword {
+		child, ok := node.children[r]
+		if !ok {
+			child = &trieNode{children: make(map[rune]*trieNode)}
+			node.children[r] = child
+		}
+		node = child
+	}
+	if node.entry == nil {
+		node.entry = &entry
+	}
+}
+
+// longestMatch 返回从text开头起最长的关键词及其字节长度，没有匹配时返回nil
+func (t *keywordTrie) longestMatch(text string) (*trieEntry, int) {
+	var matched *trieEntry
+	matchedLen := 0
+	node := t.root
+	for i, r := range text {
+		child, ok := node.children[r]
+		if !ok {
+			break
+		}
+		node = child
+		if node.entry != nil {
+			matched = node.entry
+			matchedLen = i + utf8.RuneLen(r)
+		}
+	}
+	return matched, matchedLen
+}
+
+// sortedKeys 按字典序返回map的键，保证构建结果稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tokenize 将下注文本切分为词法单元，切分前先规范化繁体字和全角字符
+// 词法单元的文字为规范化后的文字，位置为在原文中的位置
+func (p *IntelligentBetParser) tokenize(text string) []Token {
+	text, offsets := normalizeTextWithOffsets(text)
+	tokens := make([]Token, 0)
+	unknownStart := -1
+
+	flushUnknown := func(end int) {
+		if unknownStart < 0 {
+			return
+		}
+		tokens = append(tokens, Token{Type: TokenUnknown, Text: text[unknownStart:end], Start: unknownStart, End: end})
+		unknownStart = -1
+	}
+
+	pos := 0
+	for pos < len(text) {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+
+		// 关键词（含以数字开头的尾数关键词，如"5尾"）
+		if token, ok := p.matchKeyword(text, pos, unknownStart >= 0); ok {
+			flushUnknown(pos)
+			tokens = append(tokens, token)
+			pos = token.End
+			continue
+		}
+
+		// 数字：紧跟在金额关键词后为金额，后接"组"为组数声明，否则为号码
+		if isASCIIDigit(r) {
+			flushUnknown(pos)
+			end := pos
+			for end < len(text) && isASCIIDigit(rune(text[end])) {
+				end++
+			}
+			token := Token{Type: TokenNumber, Text: text[pos:end], Value: text[pos:end], Start: pos, End: end}
+			value, _ := strconv.Atoi(token.Value)
+			if lastSignificantToken(tokens).Type == TokenEndKeyword {
+				token.Type = TokenAmount
+				token.Number = value
+			} else if groupsEnd, ok := p.matchGroupsSuffix(text, end); ok {
+				token = Token{Type: TokenDeclaration, Text: text[pos:groupsEnd], Value: declaredGroupsKeyword, Number: value, Start: pos, End: groupsEnd}
+			}
+			tokens = append(tokens, token)
+			pos = token.End
+			continue
+		}
+
+		// 金额关键词后的中文数字，如"各十"
+		if strings.ContainsRune(chineseNumerals, r) && lastSignificantToken(tokens).Type == TokenEndKeyword {
+			end := pos
+			for end < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[end:])
+				if !strings.ContainsRune(chineseNumerals, next) {
+					break
+				}
+				end += nextSize
+			}
+			if value, ok := chineseToNumber(text[pos:end]); ok {
+				flushUnknown(pos)
+				tokens = append(tokens, Token{Type: TokenAmount, Text: text[pos:end], Value: strconv.Itoa(value), Number: value, Start: pos, End: end})
+				pos = end
+				continue
+			}
+		}
+
+		// 文字：连续的无法识别文字合并为一个词法单元
+		if unicode.IsLetter(r) {
+			if unknownStart < 0 {
+				unknownStart = pos
+			}
+			pos += size
+			continue
+		}
+
+		// 分隔符：连续的空白或连续的符号合并为一个词法单元
+		flushUnknown(pos)
+		end := pos + size
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if unicode.IsSpace(next) != unicode.IsSpace(r) || unicode.IsLetter(next) || isASCIIDigit(next) {
+				break
+			}
+			end += nextSize
+		}
+		tokens = append(tokens, Token{Type: TokenSeparator, Text: text[pos:end], Start: pos, End: end})
+		pos = end
+	}
+	flushUnknown(len(text))
+
+	tokens = p.foldSetExpressions(text, tokens)
+	for i := range tokens {
+		tokens[i].Start, tokens[i].End = offsets[tokens[i].Start], offsets[tokens[i].End]
+	}
+	return tokens
+}
+
+// matchKeyword 在pos处按最长匹配识别关键词
+// 单字的体彩、下注类型、模式别名及集合运算（如"老"、"港"、"死"、"去"）与前后无法识别的文字相连时视为普通文字，避免"老板"中的"老"被识别为老澳
+// 单字的号码集合（如"马"、"大"）后接无法识别的文字时视为普通文字，避免"马上"、"大家"被识别为号码
+func (p *IntelligentBetParser) matchKeyword(text string, pos int, afterUnknown bool) (Token, bool) {
+	entry, length := p.trie.longestMatch(text[pos:])
+	if entry == nil {
+		return Token{}, false
+	}
+	end := pos + length
+
+	switch entry.tokenType {
+	case TokenLottery, TokenBetType, TokenMode, TokenSetOperator:
+		if utf8.RuneCountInString(text[pos:end]) == 1 && (afterUnknown || p.startsUnknownWord(text, end)) {
+			return Token{}, false
+		}
+	case TokenNumberSet:
+		if utf8.RuneCountInString(text[pos:end]) == 1 && p.startsUnknownWord(text, end) {
+			return Token{}, false
+		}
+	case TokenDeclaration:
+		return p.matchDeclaration(text, pos, end, entry.value)
+	}
+
+	return Token{
+		Type:     entry.tokenType,
+		Text:     text[pos:end],
+		Value:    entry.value,
+		Numbers:  entry.numbers,
+		Category: entry.category,
+		Start:    pos,
+		End:      end,
+	}, true
+}
+
+// matchDeclaration 识别声明关键词后的数值："合计：300元"、"共27组"、"组数27"
+// 声明关键词后没有数值时不视为声明
+func (p *IntelligentBetParser) matchDeclaration(text string, pos int, keywordEnd int, value string) (Token, bool) {
+	numberStart := keywordEnd
+	for numberStart < len(text) {
+		r, size := utf8.DecodeRuneInString(text[numberStart:])
+		if !unicode.IsSpace(r) && r != ':' && r != '：' {
+			break
+		}
+		numberStart += size
+	}
+	numberEnd := numberStart
+	for numberEnd < len(text) && isASCIIDigit(rune(text[numberEnd])) {
+		numberEnd++
+	}
+	if numberEnd == numberStart {
+		return Token{}, false
+	}
+	number, _ := strconv.Atoi(text[numberStart:numberEnd])
+
+	end := numberEnd
+	if groupsEnd, ok := p.matchGroupsSuffix(text, numberEnd); ok && value == declaredTotalKeyword {
+		// "共27组"为组数声明
+		value = declaredGroupsKeyword
+		end = groupsEnd
+	} else if value == declaredTotalKeyword {
+		// 合计后的单位
+		rest := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+		for _, unit := range []string{"元", "块"} {
+			if strings.HasPrefix(rest, unit) {
+				end = len(text) - len(rest) + len(unit)
+				break
+			}
+		}
+	}
+
+	return Token{Type: TokenDeclaration, Text: text[pos:end], Value: value, Number: number, Start: pos, End: end}, true
+}
+
+// matchGroupsSuffix 检查数字后是否紧跟"组"（允许空白），"组合"等关键词除外
+func (p *IntelligentBetParser) matchGroupsSuffix(text string, pos int) (int, bool) {
+	rest := strings.TrimLeftFunc(text[pos:], unicode.IsSpace)
+	if !strings.HasPrefix(rest, "组") {
+		return 0, false
+	}
+	start := len(text) - len(rest)
+	if entry, length := p.trie.longestMatch(rest); entry != nil && length > len("组") {
+		return 0, false
+	}
+	return start + len("组"), true
+}
+
+// startsUnknownWord 检查pos处是否为无法识别的文字
+func (p *IntelligentBetParser) startsUnknownWord(text string, pos int) bool {
+	if pos >= len(text) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(text[pos:])
+	if !unicode.IsLetter(r) {
+		return false
+	}
+	entry, _ := p.trie.longestMatch(text[pos:])
+	return entry == nil
+}
+
+// lastSignificantToken 返回最后一个非分隔符的词法单元
+func lastSignificantToken(tokens []Token) Token {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].Type != TokenSeparator {
+			return tokens[i]
+		}
+	}
+	return Token{Type: TokenSeparator}
+}
+
+// isASCIIDigit 检查是否为阿拉伯数字
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isNumeric 检查词法单元是否表示号码
+func (t Token) isNumeric() bool {
+	return t.Type == TokenNumber || t.Type == TokenNumberSet
+}
+
+// isJoinSeparator 检查分隔符是否为号码连接符：由同一种连接符组成（如"."、".."）
+func (t Token) isJoinSeparator() bool {
+	if t.Type != TokenSeparator {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(t.Text)
+	if !joinSeparators[first] {
+		return false
+	}
+	for _, r := range t.Text {
+		if r != first {
+			return false
+		}
+	}
+	return true
+}
+
+// numbersText 号码的规范文本：号码集合展开为两位数号码并以"-"连接
+func (t Token) numbersText() string {
+	if t.Type == TokenNumberSet {
+		return joinNumbers(t.Numbers)
+	}
+	return t.Value
+}
+
+// joinsNumbers 检查tokens[i]处的分隔符是否连接前后两个号码
+// 一串号码混用多种连接符时，出现次数最多的连接符连接同一组号码，其余连接符分隔各组，
+// 如"5-13-32，7-23-26、8-22-23"为三组；出现次数同为最多的连接符都视为连接
+func joinsNumbers(tokens []Token, i int) bool {
+	if !linksNumbers(tokens, i) {
+		return false
+	}
+
+	start, end := i, i
+	for start-2 > 0 && linksNumbers(tokens, start-2) {
+		start -= 2
+	}
+	for end+2 < len(tokens)-1 && linksNumbers(tokens, end+2) {
+		end += 2
+	}
+	counts := make(map[rune]int)
+	for k := start; k <= end; k += 2 {
+		first, _ := utf8.DecodeRuneInString(tokens[k].Text)
+		counts[first]++
+	}
+
+	own, _ := utf8.DecodeRuneInString(tokens[i].Text)
+	for r, count := range counts {
+		if r != own && count > counts[own] {
+			return false
+		}
+	}
+	return true
+}
+
+// linksNumbers 检查tokens[i]处是否为前后都是号码的连接符
+func linksNumbers(tokens []Token, i int) bool {
+	return i > 0 && i < len(tokens)-1 &&
+		tokens[i].isJoinSeparator() && tokens[i-1].isNumeric() && tokens[i+1].isNumeric()
+}
+
+// renderTokens 将词法单元还原为规范文本，如"死1.2.3各五"还原为"三中三1-2-3各5"
+// 无法识别的文字不输出，但会保留断开作用，避免前后号码被拼接
+func renderTokens(tokens []Token) string {
+	var builder strings.Builder
+	pendingSpace := false
+
+	write := func(s string) {
+		if pendingSpace && builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		pendingSpace = false
+		builder.WriteString(s)
+	}
+
+	for i, token := range tokens {
+		switch token.Type {
+		case TokenSeparator:
+			if joinsNumbers(tokens, i) {
+				builder.WriteString("-")
+				pendingSpace = false
+			} else {
+				pendingSpace = true
+			}
+		case TokenUnknown:
+			pendingSpace = true
+		case TokenNumber, TokenNumberSet:
+			// 相邻的号码直接连接为同一组，如"1鼠"
+			if i > 0 && tokens[i-1].isNumeric() {
+				builder.WriteString("-")
+			}
+			write(token.numbersText())
+		case TokenAmount:
+			// 金额紧跟金额关键词，如"各 5"还原为"各5"
+			pendingSpace = false
+			write(token.Value)
+		case TokenDeclaration:
+			pendingSpace = true
+			write(token.Value + strconv.Itoa(token.Number))
+			pendingSpace = true
+		default:
+			write(token.Value)
+		}
+	}
+
+	return builder.String()
+}
+
+// formatTokens 词法单元的调试文本，用于日志
+func formatTokens(tokens []Token) string {
+	parts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.Type == TokenSeparator {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)[%d:%d]", token.Type, token.Text, token.Start, token.End))
+	}
+	return strings.Join(parts, " ")
+}
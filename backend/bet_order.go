@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"slices"
+	"sort"
+)
+
+// 规范顺序：解析结果、继承状态、统计汇总及回复中的体彩和下注类型均按此顺序排列，保证同一输入每次输出相同
+var (
+	lotteryOrder = []string{"新澳", "老澳", "香港"}
+	betTypeOrder = []string{"三中三", "三中二", "二中二", "特碰"}
+)
+
+// sortLotteries 按新澳、老澳、香港的顺序排列体彩，其余按字典序排在后面
+func sortLotteries(lotteries []string) []string {
+	return sortByOrder(lotteries, lotteryOrder)
+}
+
+// sortBetTypes 按三中三、三中二、二中二、特碰的顺序排列下注类型，其余按字典序排在后面
+func sortBetTypes(betTypes []string) []string {
+	return sortByOrder(betTypes, betTypeOrder)
+}
+
+// sortByOrder 按order中的位置排列values（原地排序并返回），不在order中的按字典序排在后面
+func sortByOrder(values []string, order []string) []string {
+	rank := func(value string) int {
+		if index := slices.Index(order, value); index >= 0 {
+			return index
+		}
+		return len(order)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		a, b := rank(values[i]), rank(values[j])
+		if a != b {
+			return a < b
+		}
+		return a == len(order) && values[i] < values[j]
+	})
+	return values
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSortByOrder(t *testing.T) {
+	lotteries := sortLotteries([]string{"香港", "其他", "新澳", "澳", "老澳"})
+	if want := []string{"新澳", "老澳", "香港", "其他", "澳"}; !slices.Equal(lotteries, want) {
+		t.Errorf("sortLotteries = %v, want %v", lotteries, want)
+	}
+	betTypes := sortBetTypes([]string{"特碰", "二中二", "三中二", "三中三"})
+	if want := []string{"三中三", "三中二", "二中二", "特碰"}; !slices.Equal(betTypes, want) {
+		t.Errorf("sortBetTypes = %v, want %v", betTypes, want)
+	}
+}
+
+func TestParseOutputDeterministic(t *testing.T) {
+	input := "香港老澳新澳 二中二三中三 1.2.3各5\n4.5.6各10"
+
+	// 同一输入多次解析，输出（除解析时间、轮次ID外）完全相同
+	var first string
+	for i := 0; i < 20; i++ {
+		result := newTestParser().ParseBetString(BetParseRequest{Input: input})
+		roundID := result.RoundID
+		result.ParseTime = time.Time{}
+		result.RoundID = ""
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 下注ID以轮次ID开头
+		output := strings.ReplaceAll(string(data), `"`+roundID+`_bet_`, `"_bet_`)
+		if first == "" {
+			first = output
+		} else if output != first {
+			t.Fatalf("第%d次解析结果与第1次不同", i+1)
+		}
+	}
+
+	// 沿用的下注类型按规范顺序列出
+	result := newTestParser().ParseBetString(BetParseRequest{Input: input})
+	if !slices.ContainsFunc(result.ParsedBets[1].Warnings, func(warning string) bool {
+		return strings.Contains(warning, "沿用上一笔的下注类型: 三中三、二中二")
+	}) {
+		t.Errorf("Warnings = %q, want 按三中三、二中二的顺序沿用", result.ParsedBets[1].Warnings)
+	}
+
+	reply, err := renderReply(ReplyTemplates{}, ReplyAccepted, result, decimal.Zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "收到 新澳 三中三 1组×5=5 新澳 二中二 3组×5=15"; !strings.HasPrefix(reply, want) {
+		t.Errorf("回复 = %q, want 以%q开头", reply, want)
+	}
+}
@@ -3,6 +3,7 @@ package backend
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -22,12 +23,11 @@ func NewBetParser(app *App) *BetParser {
 
 // 获取配置数据的方法
 func (p *BetParser) getZodiacMap() map[string][]int {
-	config := p.app.GetZodiacConfig()
-	return map[string][]int{
-		"鼠": config.Rat, "牛": config.Ox, "虎": config.Tiger, "兔": config.Rabbit,
-		"龙": config.Dragon, "蛇": config.Snake, "马": config.Horse, "羊": config.Goat,
-		"猴": config.Monkey, "鸡": config.Rooster, "狗": config.Dog, "猪": config.Pig,
+	zodiacMap := make(map[string][]int)
+	for _, set := range numberSetsByCategory(p.app.createParserConfig(time.Now()).NumberSets, NumberSetZodiac) {
+		zodiacMap[set.Name] = set.Numbers
 	}
+	return zodiacMap
 }
 
 func (p *BetParser) getBetTypeAlias() map[string][]string {
@@ -448,8 +448,8 @@ func (p *BetParser) extractNumbers(str string) []int {
 func (p *BetParser) extractBetTypes(str string) []string {
 	types := []string{}
 	betTypeAlias := p.getBetTypeAlias()
-	for standard, aliases := range betTypeAlias {
-		for _, alias := range aliases {
+	for _, standard := range betTypeOrder {
+		for _, alias := range betTypeAlias[standard] {
 			if strings.Contains(str, alias) {
 				types = append(types, standard)
 				break
@@ -472,8 +472,8 @@ func (p *BetParser) extractBetTypes(str string) []string {
 
 func (p *BetParser) normalizeLottery(str string) string {
 	lotteryAlias := p.getLotteryAlias()
-	for standard, aliases := range lotteryAlias {
-		for _, alias := range aliases {
+	for _, standard := range lotteryOrder {
+		for _, alias := range lotteryAlias[standard] {
 			if strings.Contains(str, alias) {
 				return standard
 			}
@@ -530,9 +530,26 @@ func (p *BetParser) generateSummary(results []ParsedBet) []BetTypeSummary {
 		summary[key].TotalAmount += bet.TotalAmount
 	}
 
+	// 按体彩、下注类型的规范顺序输出
 	result := []BetTypeSummary{}
+	lotteries := make([]string, 0)
+	betTypes := make([]string, 0)
 	for _, s := range summary {
-		result = append(result, *s)
+		if !slices.Contains(lotteries, s.Lottery) {
+			lotteries = append(lotteries, s.Lottery)
+		}
+		if !slices.Contains(betTypes, s.Type) {
+			betTypes = append(betTypes, s.Type)
+		}
+	}
+	sortLotteries(lotteries)
+	sortBetTypes(betTypes)
+	for _, lottery := range lotteries {
+		for _, betType := range betTypes {
+			if s, exists := summary[lottery+"_"+betType]; exists {
+				result = append(result, *s)
+			}
+		}
 	}
 
 	return result
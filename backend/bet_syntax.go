@@ -0,0 +1,197 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// numberGroup 一组号码，如"1-2-3"，号码集合展开为"01-13-25-37-49"
+type numberGroup struct {
+	Text       string // 规范文本，号码以"-"连接
+	Count      int    // 号码个数（去重前）
+	Expression string // 号码集合表达式原文，如"红波去12"，不含号码集合时为空
+}
+
+// dragExpr 拖码表达式，如"1-2-3拖4-5-6"
+type dragExpr struct {
+	Text       string        // 规范文本
+	Parts      []numberGroup // 以"拖"分隔的各组号码
+	Expression string        // 号码集合表达式原文，如"绿波双拖5尾"，不含号码集合时为空
+}
+
+// betSyntax 单笔下注的语法结构，由词法单元分析得到
+type betSyntax struct {
+	Lotteries  []string        // 体彩，按出现顺序去重
+	BetTypes   map[string]bool // 出现的下注类型
+	Complex    bool            // 是否明确写了"复式"
+	HasDrag    bool            // 是否出现"拖"
+	Groups     []numberGroup   // 号码组（不含拖码）
+	Drags      []dragExpr      // 拖码表达式
+	Amount     decimal.Decimal // 单组金额，零值表示未标明
+	AmountText string          // 金额表达式，如"各5"
+}
+
+// syntaxItem 分析过程中的中间单元：号码组、"拖"或断开标记
+type syntaxItem struct {
+	group *numberGroup
+	drag  bool
+}
+
+// analyzeBetTokens 分析单笔下注的词法单元
+func (p *IntelligentBetParser) analyzeBetTokens(tokens []Token) betSyntax {
+	syntax := betSyntax{
+		Lotteries: make([]string, 0),
+		BetTypes:  make(map[string]bool),
+		Amount:    decimal.Zero,
+	}
+
+	items := make([]syntaxItem, 0)
+	current := make([]string, 0)
+	expressions := make([]string, 0)
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		text := strings.Join(current, "-")
+		items = append(items, syntaxItem{group: &numberGroup{Text: text, Count: len(strings.Split(text, "-")), Expression: strings.Join(expressions, " ")}})
+		current = make([]string, 0)
+		expressions = make([]string, 0)
+	}
+	// 除号码连接符、空白外的内容都会断开号码组及拖码表达式
+	breakItems := func() {
+		flush()
+		items = append(items, syntaxItem{})
+	}
+
+	for i, token := range tokens {
+		switch token.Type {
+		case TokenNumber, TokenNumberSet:
+			current = append(current, token.numbersText())
+			if token.Type == TokenNumberSet {
+				expressions = append(expressions, token.Value)
+			}
+		case TokenSeparator:
+			if !joinsNumbers(tokens, i) {
+				flush()
+			}
+		case TokenLottery:
+			breakItems()
+			if !slices.Contains(syntax.Lotteries, token.Value) {
+				syntax.Lotteries = append(syntax.Lotteries, token.Value)
+			}
+		case TokenBetType:
+			breakItems()
+			syntax.BetTypes[token.Value] = true
+		case TokenMode:
+			flush()
+			switch token.Value {
+			case "拖":
+				syntax.HasDrag = true
+				items = append(items, syntaxItem{drag: true})
+			case "复式":
+				syntax.Complex = true
+			}
+		case TokenAmount:
+			breakItems()
+			if syntax.AmountText == "" {
+				if stake := findStake(tokens[:i+1]); stake != nil {
+					syntax.Amount = decimal.NewFromInt(int64(token.Number))
+					syntax.AmountText = renderTokens(stake)
+				}
+			}
+		default:
+			breakItems()
+		}
+	}
+	flush()
+
+	// 号码组与"拖"交替出现时组成拖码表达式
+	for i := 0; i < len(items); i++ {
+		if items[i].group == nil {
+			continue
+		}
+		parts := []numberGroup{*items[i].group}
+		j := i
+		for j+2 < len(items) && items[j+1].drag && items[j+2].group != nil {
+			parts = append(parts, *items[j+2].group)
+			j += 2
+		}
+		if len(parts) == 1 {
+			syntax.Groups = append(syntax.Groups, parts[0])
+			continue
+		}
+
+		texts := make([]string, len(parts))
+		expressions := make([]string, len(parts))
+		hasExpression := false
+		for k, part := range parts {
+			texts[k] = part.Text
+			expressions[k] = part.Text
+			if part.Expression != "" {
+				expressions[k] = part.Expression
+				hasExpression = true
+			}
+		}
+		drag := dragExpr{Text: strings.Join(texts, "拖"), Parts: parts}
+		if hasExpression {
+			drag.Expression = strings.Join(expressions, "拖")
+		}
+		syntax.Drags = append(syntax.Drags, drag)
+		i = j
+	}
+
+	return syntax
+}
+
+// describe 号码组的描述文本：规范文本，含号码集合时附上表达式原文，如"01-07-13(红单)"
+func (g numberGroup) describe() string {
+	if g.Expression == "" {
+		return g.Text
+	}
+	return fmt.Sprintf("%s(%s)", g.Text, g.Expression)
+}
+
+// describe 拖码表达式的描述文本，含号码集合时附上表达式原文
+func (d dragExpr) describe() string {
+	if d.Expression == "" {
+		return d.Text
+	}
+	return fmt.Sprintf("%s(%s)", d.Text, d.Expression)
+}
+
+// betTypeFlags 下注类型标识
+func (s betSyntax) betTypeFlags() BetTypeFlags {
+	return BetTypeFlags{
+		HasThreeOfThree: s.BetTypes["三中三"],
+		HasThreeOfTwo:   s.BetTypes["三中二"],
+		HasTwoOfTwo:     s.BetTypes["二中二"],
+		HasSpecial:      s.BetTypes["特碰"],
+	}
+}
+
+// orderedBetTypes 按规范顺序返回出现的下注类型
+func (s betSyntax) orderedBetTypes() []string {
+	betTypes := make([]string, 0, len(s.BetTypes))
+	for _, betType := range betTypeOrder {
+		if s.BetTypes[betType] {
+			betTypes = append(betTypes, betType)
+		}
+	}
+	return betTypes
+}
+
+// hasGroupOfSize 检查是否存在号码个数不少于n的号码组（不含拖码）
+func (s betSyntax) hasGroupOfSize(n int) bool {
+	if n <= 0 {
+		return false
+	}
+	for _, group := range s.Groups {
+		if group.Count >= n {
+			return true
+		}
+	}
+	return false
+}
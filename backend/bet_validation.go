@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 六合彩号码范围
+const (
+	MinLotteryNumber = 1
+	MaxLotteryNumber = 49
+)
+
+// isValidLotteryNumber 检查号码是否在1-49范围内
+func isValidLotteryNumber(num int) bool {
+	return num >= MinLotteryNumber && num <= MaxLotteryNumber
+}
+
+// dedupeNumbers 按出现顺序去除重复号码，返回去重后的号码及重复的号码
+func dedupeNumbers(numbers []int) ([]int, []int) {
+	seen := make(map[int]bool, len(numbers))
+	unique := make([]int, 0, len(numbers))
+	duplicates := make([]int, 0)
+	for _, num := range numbers {
+		if seen[num] {
+			duplicates = append(duplicates, num)
+			continue
+		}
+		seen[num] = true
+		unique = append(unique, num)
+	}
+	return unique, duplicates
+}
+
+// parseNumberGroup 将"5-10-29"形式的号码组转换为号码列表，越界号码返回错误
+func parseNumberGroup(group string) ([]int, error) {
+	numbers := make([]int, 0)
+	for _, s := range strings.Split(group, "-") {
+		if s == "" {
+			continue
+		}
+		num, err := strconv.Atoi(s)
+		if err != nil || !isValidLotteryNumber(num) {
+			return nil, fmt.Errorf("号码%s超出范围(%d-%d)，请检查下注内容: %s", s, MinLotteryNumber, MaxLotteryNumber, group)
+		}
+		numbers = append(numbers, num)
+	}
+	return numbers, nil
+}
+
+// validateBetNumbers 校验单笔下注中的号码组
+// 越界号码直接返回错误；复式号码组中的重复号码会被去重，并返回提示信息
+func (p *IntelligentBetParser) validateBetNumbers(syntax betSyntax) ([]string, error) {
+	warnings := make([]string, 0)
+
+	// 单个号码不是号码组（如"三中三3"中的数字），生成组合时同样会忽略；拖码中的单个号码需要校验
+	groups := make([]numberGroup, 0, len(syntax.Groups))
+	for _, group := range syntax.Groups {
+		if group.Count > 1 {
+			groups = append(groups, group)
+		}
+	}
+	for _, drag := range syntax.Drags {
+		groups = append(groups, drag.Parts...)
+	}
+
+	for _, group := range groups {
+		numbers, err := parseNumberGroup(group.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, duplicates := dedupeNumbers(numbers); len(duplicates) > 0 {
+			duplicateStrs := make([]string, len(duplicates))
+			for i, num := range duplicates {
+				duplicateStrs[i] = strconv.Itoa(num)
+			}
+			warnings = append(warnings, fmt.Sprintf("号码组\"%s\"中号码%s重复，已去重", group.Text, strings.Join(duplicateStrs, "、")))
+		}
+	}
+
+	return warnings, nil
+}
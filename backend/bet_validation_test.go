@@ -0,0 +1,53 @@
+package backend
+
+import "testing"
+
+func TestValidateBetNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantErr  bool
+		groups   int
+		warnings int
+	}{
+		{"复式号码重复时去重并提示", "5,10,29,30,5复式二中二各5", false, 6, 1},
+		{"号码00超出范围", "二中二00.12各5", true, 0, 0},
+		{"号码50超出范围", "二中二1.50各5", true, 0, 0},
+		{"拖码各段重复的号码不组成一组", "二中二1.2.3拖3.4各5", false, 5, 0},
+		{"去重后号码不足一组", "二中二1.1各5", true, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError != tt.wantErr {
+				t.Fatalf("HasError = %v, want %v, errors: %v", result.HasError, tt.wantErr, result.ErrorMessages)
+			}
+			if got := result.RoundStatistics.TotalGroups; got != tt.groups {
+				t.Errorf("TotalGroups = %d, want %d", got, tt.groups)
+			}
+			bet := result.ParsedBets[0]
+			if len(bet.Warnings) != tt.warnings {
+				t.Errorf("提示信息 = %q, want %d条", bet.Warnings, tt.warnings)
+			}
+
+			// 每组号码都在1-49范围内且互不相同
+			for _, lottery := range bet.LotteryBets {
+				for _, betType := range lottery.BetTypeDetails {
+					for _, mode := range betType.Modes {
+						for _, detail := range mode.BetDetails {
+							if _, duplicates := dedupeNumbers(detail.Numbers); len(duplicates) > 0 {
+								t.Errorf("%s中号码重复: %v", betType.BetType, detail.Numbers)
+							}
+							for _, n := range detail.Numbers {
+								if !isValidLotteryNumber(n) {
+									t.Errorf("%s中号码%d超出范围", betType.BetType, n)
+								}
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultDrawCutoff 默认每期截止时间，之后的消息计入下一期
+const defaultDrawCutoff = "21:30"
+
+// importChatLog 导入导出的群聊记录：拆分为消息，按期数、玩家分组，各条消息单独解析后按组汇总
+// newParser按消息发送时间创建解析器，跨农历新年的消息按各自发送当时的生肖号码解析
+func importChatLog(content string, options ChatImportOptions, newParser func(at time.Time) *IntelligentBetParser) (ChatImportResult, error) {
+	result := ChatImportResult{
+		Batches:         make([]PlayerBetBatch, 0),
+		SkippedMessages: make([]ChatMessage, 0),
+		TotalAmount:     decimal.Zero,
+		WarningMessages: make([]string, 0),
+	}
+
+	cutoff := options.DrawCutoff
+	if cutoff == "" {
+		cutoff = defaultDrawCutoff
+	}
+	cutoffTime, err := time.Parse("15:04", cutoff)
+	if err != nil {
+		return result, fmt.Errorf("截止时间格式错误: %s，应为\"21:30\"的格式", cutoff)
+	}
+
+	messages, orphans := parseChatLog(content)
+	result.MessageCount = len(messages)
+	if len(orphans) > 0 {
+		result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("第一条消息前的内容无法确定发送者，已跳过: %s", strings.Join(orphans, " ")))
+	}
+	if len(messages) == 0 {
+		return result, errors.New("没有识别到任何消息，请检查聊天记录格式（昵称 2026-10-17 20:31:05）")
+	}
+
+	// 同一天发送的消息生肖号码相同，共用解析器
+	parsers := make(map[string]*IntelligentBetParser)
+	parserAt := func(at time.Time) *IntelligentBetParser {
+		day := at.Format(time.DateOnly)
+		if parsers[day] == nil {
+			parsers[day] = newParser(at)
+		}
+		return parsers[day]
+	}
+
+	// 按期数、玩家分组，保持各组首条消息的先后顺序
+	// 同一期的消息跨农历新年时按生肖号码表分为两组，各组的消息生肖号码相同
+	batchIndex := make(map[string]int)
+	for _, message := range messages {
+		parser := parserAt(message.Time)
+		if !parser.hasBetContent(message.Text) {
+			result.SkippedMessages = append(result.SkippedMessages, message)
+			continue
+		}
+		period := drawPeriod(message.Time, cutoffTime)
+		key := fmt.Sprintf("%s\x00%s\x00%d", period, message.Sender, parser.config.ZodiacTable.LunarYear)
+		index, ok := batchIndex[key]
+		if !ok {
+			index = len(result.Batches)
+			batchIndex[key] = index
+			result.Batches = append(result.Batches, PlayerBetBatch{Player: message.Sender, Period: period, Messages: make([]ChatMessage, 0)})
+		}
+		result.Batches[index].Messages = append(result.Batches[index].Messages, message)
+	}
+	slices.SortStableFunc(result.Batches, func(a, b PlayerBetBatch) int {
+		return strings.Compare(a.Period, b.Period)
+	})
+
+	for i := range result.Batches {
+		batch := &result.Batches[i]
+		batch.Result = parseBatchMessages(batch.Messages, options.EnabledTypes, parserAt)
+		if batch.Result.HasError {
+			result.HasError = true
+		}
+		result.TotalAmount = result.TotalAmount.Add(batch.Result.RoundStatistics.TotalAmount)
+	}
+
+	safeLogger.AppendLog(fmt.Sprintf("导入聊天记录: %d条消息, %d组下注, 跳过%d条, 总金额%s元",
+		result.MessageCount, len(result.Batches), len(result.SkippedMessages), result.TotalAmount.String()))
+	return result, nil
+}
+
+// parseBatchMessages 逐条解析同一玩家在同一期内的消息并汇总为一个结果
+// 每条消息是单独的一轮下注，体彩、下注类型及金额不跨消息继承；错误及提示注明所属消息的发送时间
+func parseBatchMessages(messages []ChatMessage, enabledTypes []string, parserAt func(at time.Time) *IntelligentBetParser) BetParsingResult {
+	parser := parserAt(messages[0].Time)
+	texts := make([]string, len(messages))
+	for i, message := range messages {
+		texts[i] = message.Text
+	}
+	result := BetParsingResult{
+		RoundID:         strconv.FormatInt(atomic.AddInt64(&roundIDCounter, 1), 10),
+		OriginalText:    strings.Join(texts, "\n"),
+		ParsedBets:      make([]SingleBetParsing, 0),
+		ErrorMessages:   make([]string, 0),
+		WarningMessages: make([]string, 0),
+		Ambiguities:     make([]BetAmbiguity, 0),
+		ZodiacTable:     parser.config.ZodiacTable,
+		ConfigVersion:   parser.config.ConfigVersion,
+		Profile:         parser.config.Profile,
+	}
+
+	for _, message := range messages {
+		round := parserAt(message.Time).ParseBetString(BetParseRequest{
+			Input:        message.Text,
+			EnabledTypes: enabledTypes,
+			UserSettings: make(map[string]interface{}),
+		})
+		sentAt := message.Time.Format(time.TimeOnly)
+		offset := len(result.ParsedBets)
+		result.ParsedBets = append(result.ParsedBets, round.ParsedBets...)
+		for _, ambiguity := range round.Ambiguities {
+			ambiguity.BetIndex += offset
+			result.Ambiguities = append(result.Ambiguities, ambiguity)
+		}
+		if round.HasError {
+			result.HasError = true
+		}
+		for _, text := range round.ErrorMessages {
+			result.ErrorMessages = append(result.ErrorMessages, fmt.Sprintf("%s的消息: %s", sentAt, text))
+		}
+		for _, text := range round.WarningMessages {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("%s的消息: %s", sentAt, text))
+		}
+	}
+	result.RoundStatistics = parser.generateRoundStatistics(result.ParsedBets)
+	result.ParseTime = time.Now()
+	return result
+}
+
+// parseChatLog 将导出的聊天记录拆分为消息
+// 每条消息以"昵称 2026-10-17 20:31:05"开头，其后各行为消息内容；第一条消息前的内容无法归属，单独返回
+func parseChatLog(content string) ([]ChatMessage, []string) {
+	messages := make([]ChatMessage, 0)
+	orphans := make([]string, 0)
+
+	var current *ChatMessage
+	body := make([]string, 0)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(strings.Join(body, "\n"))
+		messages = append(messages, *current)
+		body = make([]string, 0)
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if match := senderHeaderRe.FindStringSubmatch(line); match != nil {
+			if sentAt, err := parseChatTime(match[2], match[3]); err == nil {
+				flush()
+				current = &ChatMessage{Sender: strings.TrimSpace(match[1]), Time: sentAt}
+				continue
+			}
+		}
+		if current == nil {
+			if trimmed := strings.TrimSpace(line); trimmed != "" {
+				orphans = append(orphans, trimmed)
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return messages, orphans
+}
+
+// parseChatTime 解析消息头中的日期和时间，日期支持"2026-10-17"、"2026/10/17"、"2026年10月17日"
+func parseChatTime(date string, clock string) (time.Time, error) {
+	date = strings.NewReplacer("年", "-", "月", "-", "日", "", "/", "-").Replace(date)
+	if strings.Count(clock, ":") == 1 {
+		clock += ":00"
+	}
+	return time.ParseInLocation("2006-1-2 15:04:05", date+" "+clock, time.Local)
+}
+
+// drawPeriod 计算消息所属的期数：截止时间之后的消息计入次日一期
+func drawPeriod(sentAt time.Time, cutoff time.Time) string {
+	minutes := sentAt.Hour()*60 + sentAt.Minute()
+	if minutes >= cutoff.Hour()*60+cutoff.Minute() {
+		sentAt = sentAt.AddDate(0, 0, 1)
+	}
+	return sentAt.Format("2006-01-02")
+}
+
+// hasBetContent 检查消息是否包含下注内容（号码、号码集合、体彩、下注类型或金额）
+func (p *IntelligentBetParser) hasBetContent(text string) bool {
+	stripped, _ := p.stripWeChatNoise(text)
+	for _, token := range p.tokenize(stripped) {
+		switch token.Type {
+		case TokenNumber, TokenNumberSet, TokenLottery, TokenBetType, TokenAmount:
+			return true
+		}
+	}
+	return false
+}
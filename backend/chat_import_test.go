@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestImportChatLogUsesMessageTime(t *testing.T) {
+	newParser := func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 0, at))
+	}
+	// 2026年农历新年为2月17日，除夕与初一的下注按各自发送当时的生肖号码解析
+	content := `张三 2026-02-16 20:00:00
+三中三1.2.3各5
+张三 2026-02-17 20:00:00
+三中三4.5.6各5`
+
+	result, err := importChatLog(content, ChatImportOptions{}, newParser)
+	if err != nil {
+		t.Fatalf("importChatLog: %v", err)
+	}
+	if len(result.Batches) != 2 {
+		t.Fatalf("Batches = %d, want 2", len(result.Batches))
+	}
+	for i, want := range []int{2025, 2026} {
+		if got := result.Batches[i].Result.ZodiacTable.LunarYear; got != want {
+			t.Errorf("第%d组生肖号码表年份 = %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestImportChatLogParsesMessagesSeparately(t *testing.T) {
+	newParser := func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 0, at))
+	}
+	// 第一条消息未写金额，不沿用下一条消息的金额
+	content := `张三 2026-10-17 20:00:00
+三中三1.2.3
+张三 2026-10-17 20:05:00
+二中二4.5各10
+张三 2026-10-17 20:10:00
+三中三6.7.8各5`
+
+	result, err := importChatLog(content, ChatImportOptions{}, newParser)
+	if err != nil {
+		t.Fatalf("importChatLog: %v", err)
+	}
+	if len(result.Batches) != 1 {
+		t.Fatalf("Batches = %d, want 1", len(result.Batches))
+	}
+	batch := result.Batches[0].Result
+	if !batch.HasError || !result.HasError {
+		t.Errorf("未写金额的消息没有报错")
+	}
+	if len(batch.ErrorMessages) == 0 || !strings.HasPrefix(batch.ErrorMessages[0], "20:00:00的消息: ") {
+		t.Errorf("错误信息 = %v, want 注明20:00:00的消息", batch.ErrorMessages)
+	}
+	if len(batch.ParsedBets) != 3 {
+		t.Errorf("下注笔数 = %d, want 3", len(batch.ParsedBets))
+	}
+	if got := batch.RoundStatistics.TotalAmount; !got.Equal(decimal.NewFromInt(15)) || !result.TotalAmount.Equal(got) {
+		t.Errorf("总金额 = %s, 导入总金额 = %s, want 15", got, result.TotalAmount)
+	}
+	if batch.RoundStatistics.TotalGroups != 2 {
+		t.Errorf("总组数 = %d, want 2", batch.RoundStatistics.TotalGroups)
+	}
+}
@@ -1,10 +1,13 @@
 package backend
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -46,23 +49,24 @@ func getExecutableDir() (string, error) {
 }
 
 // loadSystemConfigFromFile 从文件加载系统配置（只在初始化时调用）
+// 可能删除临时文件、写入默认配置或升级后的配置，须持有写锁
 func loadSystemConfigFromFile() (*SystemConfig, error) {
-	configMutex.RLock()
-	defer configMutex.RUnlock()
+	configMutex.Lock()
+	defer configMutex.Unlock()
 	configPath, err := getConfigFilePath()
 	if err != nil {
 		return nil, err
 	}
 
+	// 删除上次写入中断残留的临时文件，原配置文件未被替换
+	removeStaleTempFiles(configPath)
+
 	// 如果配置文件不存在，返回默认配置并创建文件
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		safeLogger.AppendLog("配置文件不存在，使用默认配置并创建文件: " + configPath)
 		defaultConfig := getDefaultSystemConfig()
 		// 直接写入文件，不使用saveSystemConfigToFile避免递归锁
-		data, marshalErr := json.MarshalIndent(defaultConfig, "", "  ")
-		if marshalErr == nil {
-			os.WriteFile(configPath, data, 0644)
-		}
+		writeConfigFile(configPath, defaultConfig)
 		return defaultConfig, nil
 	}
 
@@ -72,24 +76,46 @@ func loadSystemConfigFromFile() (*SystemConfig, error) {
 		return nil, fmt.Errorf("读取配置文件失败: %v", err)
 	}
 
-	// 解析JSON
-	var config SystemConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	// 解析JSON：升级旧版本后在默认配置上逐项覆盖，旧配置文件中缺少的新增项使用默认值
+	config, fromVersion, err := decodeSystemConfig(data)
+	if err != nil {
+		if !errors.Is(err, errConfigUnreadable) {
+			return nil, err
+		}
+		// 配置文件无法解析时，备份原文件并使用默认配置
 		safeLogger.AppendLog("配置文件格式错误，使用默认配置: " + err.Error())
-		// 配置文件损坏时，备份原文件并使用默认配置
-		backupPath := configPath + ".backup"
-		os.Rename(configPath, backupPath)
+		backupPath, backupErr := writeConfigBackup(configPath, data, backupCorrupt)
+		if backupErr != nil {
+			return nil, fmt.Errorf("配置文件格式错误且备份失败: %v", backupErr)
+		}
+		safeLogger.AppendLog("已备份无法解析的配置文件: " + backupPath)
 		defaultConfig := getDefaultSystemConfig()
 		// 直接写入文件，避免递归锁
-		data, marshalErr := json.MarshalIndent(defaultConfig, "", "  ")
-		if marshalErr == nil {
-			os.WriteFile(configPath, data, 0644)
-		}
+		writeConfigFile(configPath, defaultConfig)
 		return defaultConfig, nil
 	}
+	if len(config.unknownFields) > 0 {
+		safeLogger.AppendLog(fmt.Sprintf("配置文件中有无法识别的配置项，将原样保留: %s", strings.Join(sortedKeys(config.unknownFields), "、")))
+	}
+
+	// 校验配置内容，不合法的配置不加载，配置文件保留原样以便修正
+	if err := validateSystemConfig(config); err != nil {
+		return nil, fmt.Errorf("配置文件内容不合法: %v", err)
+	}
+
+	// 升级后的配置写回文件，升级前的文件保留备份
+	if fromVersion < currentSchemaVersion {
+		backupPath, err := writeConfigBackup(configPath, data, fmt.Sprintf("schema%d", fromVersion))
+		if err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("备份升级前的配置文件失败，暂不写回: %v", err))
+		} else {
+			safeLogger.AppendLog("已备份升级前的配置文件: " + backupPath)
+			writeConfigFile(configPath, config)
+		}
+	}
 
 	safeLogger.AppendLog("成功从文件加载系统配置: " + configPath)
-	return &config, nil
+	return config, nil
 }
 
 // saveSystemConfigToFile 保存系统配置到文件（线程安全）
@@ -102,54 +128,73 @@ func saveSystemConfigToFile(config *SystemConfig) error {
 		return err
 	}
 
-	// 序列化为JSON
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := marshalConfigFile(config)
 	if err != nil {
-		return fmt.Errorf("序列化配置失败: %v", err)
+		return err
 	}
 
-	// 写入文件
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("写入配置文件失败: %v", err)
+	// 覆盖前备份原文件，内容未变化时不备份
+	if previous, err := os.ReadFile(configPath); err == nil && !bytes.Equal(previous, data) {
+		if backupPath, err := writeConfigBackup(configPath, previous, backupOnSave); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("备份配置文件失败: %v", err))
+		} else {
+			safeLogger.AppendLog("已备份原配置文件: " + backupPath)
+			pruneConfigBackups(configPath, backupOnSave, maxConfigBackups)
+		}
+	}
+
+	if err := writeConfigData(configPath, data); err != nil {
+		return err
 	}
 
 	safeLogger.AppendLog("成功保存系统配置到文件: " + configPath)
+
+	// 记录配置版本，配置文件已保存，记录失败只写日志
+	if _, err := configHistory.record(config); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("记录配置版本失败: %v", err))
+	}
+	return nil
+}
+
+// writeConfigFile 序列化并写入配置文件（不加锁，调用方负责加锁）
+func writeConfigFile(configPath string, config *SystemConfig) error {
+	data, err := marshalConfigFile(config)
+	if err != nil {
+		return err
+	}
+	return writeConfigData(configPath, data)
+}
+
+// marshalConfigFile 按当前结构版本序列化配置
+func marshalConfigFile(config *SystemConfig) ([]byte, error) {
+	config.SchemaVersion = currentSchemaVersion
+	data, err := encodeSystemConfig(config)
+	if err != nil {
+		safeLogger.AppendLog("序列化配置失败: " + err.Error())
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	return data, nil
+}
+
+// writeConfigData 写入配置文件，先写临时文件再替换，写入中断时原文件不受影响
+func writeConfigData(configPath string, data []byte) error {
+	if err := writeFileAtomic(configPath, data); err != nil {
+		safeLogger.AppendLog("写入配置文件失败: " + err.Error())
+		return fmt.Errorf("写入配置文件失败: %v", err)
+	}
 	return nil
 }
 
 // getDefaultSystemConfig 获取默认系统配置
 func getDefaultSystemConfig() *SystemConfig {
 	return &SystemConfig{
-		ZodiacConfig: ZodiacConfig{
-			Rat:     []int{1, 13, 25, 37, 49},
-			Ox:      []int{2, 14, 26, 38},
-			Tiger:   []int{3, 15, 27, 39},
-			Rabbit:  []int{4, 16, 28, 40},
-			Dragon:  []int{5, 17, 29, 41},
-			Snake:   []int{6, 18, 30, 42},
-			Horse:   []int{7, 19, 31, 43},
-			Goat:    []int{8, 20, 32, 44},
-			Monkey:  []int{9, 21, 33, 45},
-			Rooster: []int{10, 22, 34, 46},
-			Dog:     []int{11, 23, 35, 47},
-			Pig:     []int{12, 24, 36, 48},
-		},
-		ColorConfig: ColorConfig{
-			Red:   []int{1, 2, 7, 8, 12, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46},
-			Green: []int{5, 6, 11, 16, 17, 21, 22, 27, 28, 32, 33, 38, 39, 43, 44, 49},
-			Blue:  []int{3, 4, 9, 10, 14, 15, 20, 25, 26, 31, 36, 37, 41, 42, 47, 48},
-		},
-		TailConfig: TailConfig{
-			Tail0: []int{10, 20, 30, 40},
-			Tail1: []int{1, 11, 21, 31, 41},
-			Tail2: []int{2, 12, 22, 32, 42},
-			Tail3: []int{3, 13, 23, 33, 43},
-			Tail4: []int{4, 14, 24, 34, 44},
-			Tail5: []int{5, 15, 25, 35, 45},
-			Tail6: []int{6, 16, 26, 36, 46},
-			Tail7: []int{7, 17, 27, 37, 47},
-			Tail8: []int{8, 18, 28, 38, 48},
-			Tail9: []int{9, 19, 29, 39, 49},
+		SchemaVersion: currentSchemaVersion,
+		ActiveProfile: DefaultProfileName,
+		Profiles:      []ConfigProfile{},
+		NumberSets:    defaultNumberSets(),
+		ZodiacSchedule: ZodiacSchedule{
+			AutoUpdate:   true,
+			NewYearDates: maps.Clone(defaultLunarNewYears),
 		},
 		BetTypeAliases: BetTypeAliases{
 			ThreeOfThree: []string{"死", "三中三", "三全中", "3中3"},
@@ -190,6 +235,23 @@ func getDefaultSystemConfig() *SystemConfig {
 				Rebate:    0.05, // 默认回水 5%
 			},
 		},
+		ParserOptions: ParserOptions{
+			StrictUnknownText: false, // 默认忽略无法识别的中文并提示
+			WeChatFilter: WeChatFilterOptions{
+				QuotedReplies: true,
+				Mentions:      true,
+				EmojiCodes:    true,
+				RecallNotices: true,
+				SenderHeaders: true,
+			},
+			PlayerHeaderPatterns: []string{defaultPlayerHeaderPattern},
+		},
+		ReplyTemplates: ReplyTemplates{
+			Accepted:  defaultAcceptedTemplate,
+			Rejected:  defaultRejectedTemplate,
+			Settled:   defaultSettledTemplate,
+			Statement: defaultStatementTemplate,
+		},
 	}
 }
 
@@ -210,14 +272,9 @@ func InitializeConfig() error {
 		safeLogger.AppendLog("配置文件不存在，创建默认配置: " + configPath)
 		defaultConfig := getDefaultSystemConfig()
 		// 直接写入文件，避免递归调用造成死锁
-		data, marshalErr := json.MarshalIndent(defaultConfig, "", "  ")
-		if marshalErr != nil {
-			safeLogger.AppendLog("序列化默认配置失败: " + marshalErr.Error())
-			return marshalErr
-		}
-		if writeErr := os.WriteFile(configPath, data, 0644); writeErr != nil {
-			safeLogger.AppendLog("创建默认配置文件失败: " + writeErr.Error())
-			return writeErr
+		if err := writeConfigFile(configPath, defaultConfig); err != nil {
+			safeLogger.AppendLog("创建默认配置文件失败: " + err.Error())
+			return err
 		}
 	}
 
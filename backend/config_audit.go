@@ -0,0 +1,359 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigAuditFileName 配置审计日志文件，每行一条修改记录，只追加不修改
+const ConfigAuditFileName = "system_config_audit.jsonl"
+
+// configAuditStore 配置修改审计日志
+type configAuditStore struct {
+	mutex   sync.Mutex
+	loaded  bool
+	entries []ConfigAuditEntry
+}
+
+// 全局配置审计日志
+var configAudit = &configAuditStore{}
+
+// getConfigAuditFilePath 获取配置审计日志文件路径，与配置文件位于同一目录
+func getConfigAuditFilePath() (string, error) {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), ConfigAuditFileName), nil
+}
+
+// load 读取审计日志文件（只读取一次）
+func (s *configAuditStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	path, err := getConfigAuditFilePath()
+	if err != nil {
+		return err
+	}
+
+	s.entries = make([]ConfigAuditEntry, 0)
+	err = readJSONLines(path, "配置审计日志", func(line []byte) error {
+		var entry ConfigAuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		s.entries = append(s.entries, entry)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.loaded = true
+	return nil
+}
+
+// record 追加审计记录，分配记录ID
+func (s *configAuditStore) record(entry ConfigAuditEntry) (ConfigAuditEntry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.load(); err != nil {
+		return entry, err
+	}
+
+	entry.ID = 1
+	if n := len(s.entries); n > 0 {
+		entry.ID = s.entries[n-1].ID + 1
+	}
+	path, err := getConfigAuditFilePath()
+	if err != nil {
+		return entry, err
+	}
+	if err := appendJSONLine(path, "配置审计日志", entry); err != nil {
+		return entry, err
+	}
+	s.entries = append(s.entries, entry)
+	return entry, nil
+}
+
+// list 所有审计记录，按时间从新到旧排列
+func (s *configAuditStore) list() []ConfigAuditEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entries := make([]ConfigAuditEntry, 0)
+	if err := s.load(); err != nil {
+		safeLogger.AppendLog(err.Error())
+		return entries
+	}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entries = append(entries, s.entries[i])
+	}
+	return entries
+}
+
+// get 返回指定ID的审计记录
+func (s *configAuditStore) get(id int) (*ConfigAuditEntry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	for i := range s.entries {
+		if s.entries[i].ID == id {
+			entry := s.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, fmt.Errorf("配置修改记录不存在: %d", id)
+}
+
+// systemOperator 未设置操作员时使用的系统用户名
+func systemOperator() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return "未知"
+}
+
+// revertConfigChanges 在配置副本上撤销修改：各配置项恢复为修改前的值
+// 配置项在这次修改之后又被修改过时不撤销，返回错误
+func revertConfigChanges(current *SystemConfig, changes []ConfigChange) (*SystemConfig, error) {
+	root, err := configJSONValue(current)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]string, 0)
+	restored := 0
+	for _, change := range changes {
+		segments, err := parseConfigPath(change.Path)
+		if err != nil {
+			return nil, err
+		}
+		value, _ := getJSONPath(root, segments)
+		current := jsonText(value)
+		if current == change.Before {
+			restored++
+		}
+		if current != change.After {
+			changed = append(changed, change.Path)
+		}
+	}
+	if restored == len(changes) {
+		return nil, fmt.Errorf("配置已是修改前的值，无需撤销")
+	}
+	if len(changed) > 0 {
+		return nil, fmt.Errorf("以下配置项在这次修改之后又被修改过，无法撤销: %s", strings.Join(changed, "、"))
+	}
+
+	for _, change := range changes {
+		segments, _ := parseConfigPath(change.Path)
+		var before interface{}
+		if change.Before != "" {
+			if err := json.Unmarshal([]byte(change.Before), &before); err != nil {
+				return nil, fmt.Errorf("配置项%s的修改前的值格式错误: %v", change.Path, err)
+			}
+		}
+		updated, err := setJSONPath(root, segments, before, change.Before == "")
+		if err != nil {
+			return nil, err
+		}
+		root = updated.(map[string]interface{})
+	}
+
+	result := cloneSystemConfig(current)
+	fields := systemConfigFields(&result)
+	for key, value := range root {
+		field, known := fields[key]
+		if !known {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("序列化配置失败: %v", err)
+		}
+		if err := decodeConfigField(field, data); err != nil {
+			return nil, fmt.Errorf("撤销后的配置项%s格式错误: %v", key, err)
+		}
+	}
+	return &result, nil
+}
+
+// configPathSegment 配置项路径的一段：字段名，或带名称的对象数组中的名称
+type configPathSegment struct {
+	key   string
+	named bool
+}
+
+// parseConfigPath 解析diffSystemConfigs生成的配置项路径，如"number_sets[红波].aliases"
+func parseConfigPath(path string) ([]configPathSegment, error) {
+	segments := make([]configPathSegment, 0)
+	rest := path
+	for rest != "" {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("配置项路径格式错误: %s", path)
+			}
+			segments = append(segments, configPathSegment{key: rest[1:end], named: true})
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("配置项路径格式错误: %s", path)
+		}
+		segments = append(segments, configPathSegment{key: rest[:end]})
+		rest = rest[end:]
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("配置项路径为空")
+	}
+	return segments, nil
+}
+
+// getJSONPath 按路径取JSON值
+func getJSONPath(value interface{}, segments []configPathSegment) (interface{}, bool) {
+	for _, segment := range segments {
+		if segment.named {
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i := namedJSONIndex(list, segment.key)
+			if i < 0 {
+				return nil, false
+			}
+			value = list[i]
+			continue
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment.key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setJSONPath 按路径设置JSON值，remove为true时删除该项，返回修改后的值
+func setJSONPath(value interface{}, segments []configPathSegment, newValue interface{}, remove bool) (interface{}, error) {
+	segment := segments[0]
+	last := len(segments) == 1
+
+	if segment.named {
+		list, ok := value.([]interface{})
+		if !ok && value != nil {
+			return nil, fmt.Errorf("配置项%s不是列表", segment.key)
+		}
+		i := namedJSONIndex(list, segment.key)
+		switch {
+		case last && remove:
+			if i >= 0 {
+				list = append(list[:i:i], list[i+1:]...)
+			}
+		case last && i >= 0:
+			list[i] = newValue
+		case last:
+			list = append(list, newValue)
+		case i < 0:
+			return nil, fmt.Errorf("配置项%s不存在", segment.key)
+		default:
+			child, err := setJSONPath(list[i], segments[1:], newValue, remove)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = child
+		}
+		return list, nil
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		if value != nil {
+			return nil, fmt.Errorf("配置项%s的上级不是对象", segment.key)
+		}
+		object = make(map[string]interface{})
+	}
+	switch {
+	case last && remove:
+		delete(object, segment.key)
+	case last:
+		object[segment.key] = newValue
+	default:
+		child, err := setJSONPath(object[segment.key], segments[1:], newValue, remove)
+		if err != nil {
+			return nil, err
+		}
+		object[segment.key] = child
+	}
+	return object, nil
+}
+
+// namedJSONIndex 在带名称的对象数组中查找名称，不存在时返回-1
+func namedJSONIndex(list []interface{}, name string) int {
+	for i, item := range list {
+		if object, ok := item.(map[string]interface{}); ok && object["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// auditConfigChange 记录配置修改的审计日志，before为修改前的配置，没有变化时不记录
+// 配置已保存，记录失败只写日志
+func (a *App) auditConfigChange(section string, before *SystemConfig) {
+	a.recordConfigAudit(section, "", before, 0)
+}
+
+// recordConfigAudit 比较修改前后的配置并记录审计日志
+// operator为空时使用当前操作员，revertOf为撤销的审计记录ID
+func (a *App) recordConfigAudit(section string, operator string, before *SystemConfig, revertOf int) {
+	a.mutex.RLock()
+	changes, err := diffSystemConfigs(before, a.systemConfig)
+	if operator == "" {
+		operator = a.operator
+	}
+	a.mutex.RUnlock()
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("记录配置修改失败: %v", err))
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+	if operator == "" {
+		operator = systemOperator()
+	}
+
+	entry, err := configAudit.record(ConfigAuditEntry{
+		Section:       section,
+		Operator:      operator,
+		Timestamp:     time.Now(),
+		ConfigVersion: configHistory.latest(),
+		Changes:       changes,
+		RevertOf:      revertOf,
+	})
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("记录配置修改失败: %v", err))
+		return
+	}
+	safeLogger.AppendLog(fmt.Sprintf("配置修改记录#%d: %s修改了%s，%d项变化", entry.ID, operator, section, len(changes)))
+}
@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigAuditRevert(t *testing.T) {
+	before := getDefaultSystemConfig()
+	after := getDefaultSystemConfig()
+	after.OddsConfig.Special.OddsRatio = 77
+	after.BetTypeAliases.TwoOfTwo = append(after.BetTypeAliases.TwoOfTwo, "二二")
+	after.NumberSets[0].Aliases = append(after.NumberSets[0].Aliases, "老鼠")
+	after.NumberSets = append(after.NumberSets, NumberSet{Name: "自选", Category: "自定义", Aliases: []string{}, Numbers: []int{1, 2, 3}})
+
+	changes, err := diffSystemConfigs(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	for _, want := range []string{"odds_config.special.odds_ratio", "bet_type_aliases.two_of_two", "number_sets[" + after.NumberSets[0].Name + "].aliases", "number_sets[自选]"} {
+		if !strings.Contains(strings.Join(paths, "\n"), want) {
+			t.Errorf("Changes = %v, want %s", paths, want)
+		}
+	}
+
+	// 审计记录写入日志文件后读回
+	path := filepath.Join(t.TempDir(), ConfigAuditFileName)
+	entry := ConfigAuditEntry{ID: 1, Section: "测试", Operator: "张三", Timestamp: time.Now(), Changes: changes}
+	if err := appendJSONLine(path, "配置审计日志", entry); err != nil {
+		t.Fatal(err)
+	}
+	var loaded []ConfigAuditEntry
+	err = readJSONLines(path, "配置审计日志", func(line []byte) error {
+		var entry ConfigAuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		loaded = append(loaded, entry)
+		return nil
+	})
+	if err != nil || len(loaded) != 1 || loaded[0].Operator != "张三" || len(loaded[0].Changes) != len(changes) {
+		t.Fatalf("读回的审计记录 = %+v, %v", loaded, err)
+	}
+
+	// 撤销后恢复为修改前的配置
+	reverted, err := revertConfigChanges(after, loaded[0].Changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameConfig(reverted, before) {
+		t.Error("撤销后的配置与修改前不同")
+	}
+
+	// 已撤销的修改不能再次撤销
+	if _, err := revertConfigChanges(reverted, changes); err == nil {
+		t.Error("已是修改前的值时应返回错误")
+	}
+
+	// 之后又被修改过的配置项不撤销
+	later := cloneSystemConfig(after)
+	later.OddsConfig.Special.OddsRatio = 88
+	if _, err := revertConfigChanges(&later, changes); err == nil || !strings.Contains(err.Error(), "odds_config.special.odds_ratio") {
+		t.Errorf("revertConfigChanges = %v, want 赔率之后又被修改过", err)
+	}
+}
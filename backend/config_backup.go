@@ -0,0 +1,205 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigBackupDirName 配置备份目录，位于配置目录下
+const ConfigBackupDirName = "backups"
+
+// maxConfigBackups 保存配置时保留的备份个数，超出时删除最早的
+const maxConfigBackups = 20
+
+// 配置备份原因，记录在备份文件名中
+const (
+	backupOnSave  = "save"    // 保存配置前的原文件，轮换保留最近maxConfigBackups个
+	backupCorrupt = "corrupt" // 无法解析的配置文件，不会被删除
+)
+
+// configBackupTimeFormat 备份文件名中的时间格式
+const configBackupTimeFormat = "20060102-150405.000"
+
+// getConfigBackupDir 获取配置备份目录，不存在时创建
+func getConfigBackupDir(configPath string) (string, error) {
+	dir := filepath.Join(filepath.Dir(configPath), ConfigBackupDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建配置备份目录失败: %v", err)
+	}
+	return dir, nil
+}
+
+// writeConfigBackup 将配置文件内容写入带时间戳的备份文件，已存在的备份不会被覆盖
+// 备份文件名如"system_config.save-20261019-203105.123.json"，返回备份文件路径
+func writeConfigBackup(configPath string, data []byte, reason string) (string, error) {
+	dir, err := getConfigBackupDir(configPath)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSuffix(ConfigFileName, filepath.Ext(ConfigFileName))
+	base := filepath.Join(dir, fmt.Sprintf("%s.%s-%s", name, reason, time.Now().Format(configBackupTimeFormat)))
+	for i := 1; ; i++ {
+		path := base + filepath.Ext(ConfigFileName)
+		if i > 1 {
+			path = fmt.Sprintf("%s-%d%s", base, i, filepath.Ext(ConfigFileName))
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("创建配置备份失败: %v", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			os.Remove(path)
+			return "", fmt.Errorf("写入配置备份失败: %v", err)
+		}
+		if err := file.Close(); err != nil {
+			os.Remove(path)
+			return "", fmt.Errorf("写入配置备份失败: %v", err)
+		}
+		return path, nil
+	}
+}
+
+// listConfigBackups 列出配置备份，按创建时间从新到旧排列
+func listConfigBackups(configPath string) ([]ConfigBackupInfo, error) {
+	dir, err := getConfigBackupDir(configPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置备份目录失败: %v", err)
+	}
+
+	prefix := strings.TrimSuffix(ConfigFileName, filepath.Ext(ConfigFileName)) + "."
+	backups := make([]ConfigBackupInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || filepath.Ext(name) != filepath.Ext(ConfigFileName) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		// 备份时间以文件名为准，文件被复制后修改时间会变化
+		reason, rest, _ := strings.Cut(strings.TrimPrefix(name, prefix), "-")
+		createdAt := info.ModTime()
+		if len(rest) >= len(configBackupTimeFormat) {
+			if t, err := time.ParseInLocation(configBackupTimeFormat, rest[:len(configBackupTimeFormat)], time.Local); err == nil {
+				createdAt = t
+			}
+		}
+		backups = append(backups, ConfigBackupInfo{Name: name, Reason: reason, CreatedAt: createdAt, Size: info.Size()})
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backupSequence(backups[i].Name) > backupSequence(backups[j].Name)
+	})
+	return backups, nil
+}
+
+// backupSequence 同一时刻的多个备份的序号，如"...-2.json"为2，没有序号为1
+func backupSequence(name string) int {
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	if i := strings.LastIndex(stem, "-"); i >= 0 && !strings.Contains(stem[i:], ".") {
+		if n, err := strconv.Atoi(stem[i+1:]); err == nil && len(stem)-i <= 4 {
+			return n
+		}
+	}
+	return 1
+}
+
+// pruneConfigBackups 只保留最近keep个指定原因的备份
+func pruneConfigBackups(configPath string, reason string, keep int) {
+	backups, err := listConfigBackups(configPath)
+	if err != nil {
+		safeLogger.AppendLog(err.Error())
+		return
+	}
+	dir := filepath.Join(filepath.Dir(configPath), ConfigBackupDirName)
+	kept := 0
+	for _, backup := range backups {
+		if backup.Reason != reason {
+			continue
+		}
+		if kept++; kept <= keep {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, backup.Name)); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("删除旧配置备份失败: %v", err))
+		}
+	}
+}
+
+// readConfigBackup 读取备份文件内容，name须为备份目录中的文件名
+func readConfigBackup(configPath string, name string) ([]byte, error) {
+	if name == "" || filepath.Base(name) != name {
+		return nil, fmt.Errorf("配置备份名称不正确: %s", name)
+	}
+	dir, err := getConfigBackupDir(configPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("读取配置备份失败: %v", err)
+	}
+	return data, nil
+}
+
+// writeFileAtomic 先写入同目录下的临时文件并同步到磁盘，再替换目标文件
+// 写入过程中断时原文件保持不变
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	temp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	// 同步目录使重命名落盘，部分系统（如Windows）不支持，忽略错误
+	if dirFile, err := os.Open(dir); err == nil {
+		dirFile.Sync()
+		dirFile.Close()
+	}
+	return nil
+}
+
+// removeStaleTempFiles 删除写入中断后残留的临时文件
+func removeStaleTempFiles(path string) {
+	matches, _ := filepath.Glob(path + ".tmp-*")
+	for _, match := range matches {
+		if err := os.Remove(match); err == nil {
+			safeLogger.AppendLog("已删除残留的临时配置文件: " + match)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package backend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	for _, content := range []string{"first", "second"} {
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != content {
+			t.Errorf("文件内容 = %q, %v, want %q", data, err, content)
+		}
+	}
+	if matches, _ := filepath.Glob(path + ".tmp-*"); len(matches) != 0 {
+		t.Errorf("残留临时文件: %v", matches)
+	}
+
+	// 写入中断残留的临时文件在加载前删除，原文件不受影响
+	if err := os.WriteFile(path+".tmp-123", []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	removeStaleTempFiles(path)
+	if matches, _ := filepath.Glob(path + ".tmp-*"); len(matches) != 0 {
+		t.Errorf("未删除临时文件: %v", matches)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second" {
+		t.Errorf("原文件内容 = %q, want second", data)
+	}
+}
+
+func TestConfigBackupRoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigFileName)
+	config := getDefaultSystemConfig()
+	config.OddsConfig.Special.OddsRatio = 123
+	data, err := marshalConfigFile(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一时刻的多个备份不会互相覆盖
+	for i := 0; i < 3; i++ {
+		if _, err := writeConfigBackup(configPath, data, backupOnSave); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := writeConfigBackup(configPath, []byte("{"), backupCorrupt); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := listConfigBackups(configPath)
+	if err != nil || len(backups) != 4 {
+		t.Fatalf("listConfigBackups = %d个, %v, want 4", len(backups), err)
+	}
+	for i := 1; i < len(backups); i++ {
+		if backups[i].CreatedAt.After(backups[i-1].CreatedAt) {
+			t.Errorf("备份未按时间从新到旧排列: %+v", backups)
+		}
+	}
+
+	// 轮换只删除保存时的备份
+	pruneConfigBackups(configPath, backupOnSave, 2)
+	backups, _ = listConfigBackups(configPath)
+	counts := make(map[string]int)
+	for _, backup := range backups {
+		counts[backup.Reason]++
+	}
+	if counts[backupOnSave] != 2 || counts[backupCorrupt] != 1 {
+		t.Errorf("轮换后备份 = %v, want save 2个 corrupt 1个", counts)
+	}
+
+	// 从备份读回的配置与原配置相同
+	for _, backup := range backups {
+		if backup.Reason != backupOnSave {
+			continue
+		}
+		restored, err := readConfigBackup(configPath, backup.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _, err := decodeSystemConfig(restored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameConfig(decoded, config) {
+			t.Errorf("备份%s读回的配置与原配置不同", backup.Name)
+		}
+	}
+
+	if _, err := readConfigBackup(configPath, "../"+ConfigFileName); err == nil {
+		t.Error("备份目录以外的文件名应返回错误")
+	}
+}
+
+func TestRestoreLegacyConfigBackup(t *testing.T) {
+	// 没有记录版本的旧版备份：生肖、波色、尾数为单独的配置项
+	odds := getDefaultSystemConfig().OddsConfig
+	odds.Special.OddsRatio = 321
+	oddsData, err := json.Marshal(odds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := []byte(`{
+		"color_config": {"red": [1, 2, 7, 8, 12, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46]},
+		"tail_config": {"tail_0": [10, 20, 30, 40]},
+		"odds_config": ` + string(oddsData) + `
+	}`)
+	configPath := filepath.Join(t.TempDir(), ConfigFileName)
+	path, err := writeConfigBackup(configPath, legacy, backupOnSave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := readConfigBackup(configPath, filepath.Base(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, fromVersion, err := decodeSystemConfig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromVersion != 1 || config.SchemaVersion != currentSchemaVersion {
+		t.Errorf("版本 %d -> %d, want 1 -> %d", fromVersion, config.SchemaVersion, currentSchemaVersion)
+	}
+	if config.OddsConfig.Special.OddsRatio != 321 {
+		t.Errorf("特碰赔率 = %g, want 321", config.OddsConfig.Special.OddsRatio)
+	}
+	sets := make(map[string]NumberSet)
+	for _, set := range config.NumberSets {
+		sets[set.Name] = set
+	}
+	if got := sets["0尾"].Numbers; len(got) != 4 {
+		t.Errorf("0尾 = %v, want 旧配置中的号码", got)
+	}
+	if sets["金"].Category != NumberSetElement {
+		t.Errorf("升级后没有五行号码集合")
+	}
+	if _, ok := config.ZodiacSchedule.NewYearDates["2060"]; !ok {
+		t.Errorf("升级后没有补充新年日期")
+	}
+	if err := validateSystemConfig(config); err != nil {
+		t.Errorf("升级后的配置校验失败: %v", err)
+	}
+
+	// 升级后的配置保存再读回不变
+	saved, err := marshalConfigFile(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded, fromVersion, err := decodeSystemConfig(saved)
+	if err != nil || fromVersion != currentSchemaVersion || !sameConfig(reloaded, config) {
+		t.Errorf("保存后读回的配置不同: 版本%d, %v", fromVersion, err)
+	}
+}
@@ -0,0 +1,314 @@
+package backend
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ConfigBundleFormat 配置包格式标识
+const ConfigBundleFormat = "six33-config-bundle"
+
+// configBundleVersion 配置包格式版本
+const configBundleVersion = 1
+
+// 配置包导入方式
+const (
+	ConfigImportMerge   = "merge"   // 合并：对象逐项覆盖，别名列表取并集，号码集合按名称合并
+	ConfigImportReplace = "replace" // 替换：配置包中的配置项整体替换当前配置
+)
+
+// configSectionGroups 导出时可使用的配置项分组
+var configSectionGroups = map[string][]string{
+	"aliases": {"number_sets", "bet_type_aliases", "keyword_aliases"},
+	"odds":    {"odds_config"},
+}
+
+// ConfigBundle 配置包：导出的配置项及校验和，用于在多台机器之间同步配置
+// 校验和用于发现文件损坏或被截断，导入前须校验通过
+type ConfigBundle struct {
+	Format        string                     `json:"format"`         // 格式标识，固定为ConfigBundleFormat
+	Version       int                        `json:"version"`        // 配置包格式版本
+	SchemaVersion int                        `json:"schema_version"` // 配置结构版本，须与导入的程序一致
+	ExportedAt    time.Time                  `json:"exported_at"`    // 导出时间
+	Sections      []string                   `json:"sections"`       // 包含的配置项，如"odds_config"
+	Config        map[string]json.RawMessage `json:"config"`         // 配置项内容
+	Checksum      string                     `json:"checksum"`       // 配置内容的SHA-256校验和
+}
+
+// ConfigImportPreview 导入配置包的预览
+type ConfigImportPreview struct {
+	Mode       string             `json:"mode"`       // 导入方式
+	Sections   []string           `json:"sections"`   // 配置包中的配置项
+	ExportedAt time.Time          `json:"exportedAt"` // 配置包导出时间
+	Changes    []ConfigChange     `json:"changes"`    // 导入后会变化的配置项
+	Errors     []ConfigFieldError `json:"errors"`     // 导入后配置不合法的项，存在时不能导入
+	Conflicts  []AliasConflict    `json:"conflicts"`  // 导入后重复的别名，存在时不能导入
+}
+
+// configSectionKeys 可导出的配置项，按SystemConfig字段顺序，不含结构版本及当前方案名称
+// 当前方案名称与号码集合等配置项对应，单独导入会使方案名称与配置不符
+func configSectionKeys() []string {
+	keys := make([]string, 0)
+	configType := reflect.TypeOf(SystemConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		name, _, _ := strings.Cut(configType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" && name != schemaVersionKey && name != "active_profile" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// resolveConfigSections 将配置项或分组名称展开为配置项，按SystemConfig字段顺序去重；为空时为全部配置项
+func resolveConfigSections(sections []string) ([]string, error) {
+	all := configSectionKeys()
+	if len(sections) == 0 {
+		return all, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, section := range sections {
+		section = strings.TrimSpace(section)
+		if group, ok := configSectionGroups[section]; ok {
+			for _, key := range group {
+				selected[key] = true
+			}
+			continue
+		}
+		if !slices.Contains(all, section) {
+			return nil, fmt.Errorf("不支持的配置项: %s", section)
+		}
+		selected[section] = true
+	}
+
+	result := make([]string, 0, len(selected))
+	for _, key := range all {
+		if selected[key] {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+// buildConfigBundle 导出配置中的指定配置项
+func buildConfigBundle(config *SystemConfig, sections []string) (*ConfigBundle, error) {
+	keys, err := resolveConfigSections(sections)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+
+	bundle := &ConfigBundle{
+		Format:        ConfigBundleFormat,
+		Version:       configBundleVersion,
+		SchemaVersion: currentSchemaVersion,
+		ExportedAt:    time.Now(),
+		Sections:      keys,
+		Config:        make(map[string]json.RawMessage, len(keys)),
+	}
+	for _, key := range keys {
+		bundle.Config[key] = raw[key]
+	}
+	if bundle.Checksum, err = configBundleChecksum(bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// configBundleChecksum 计算配置包校验和：按配置项顺序对压缩后的JSON内容计算SHA-256，与缩进格式无关
+func configBundleChecksum(bundle *ConfigBundle) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s/%d/%d\n", ConfigBundleFormat, bundle.Version, bundle.SchemaVersion)
+	for _, key := range bundle.Sections {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, bundle.Config[key]); err != nil {
+			return "", fmt.Errorf("配置项%s格式错误: %v", key, err)
+		}
+		fmt.Fprintf(hash, "%s=%s\n", key, compact.Bytes())
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// parseConfigBundle 解析并校验配置包
+func parseConfigBundle(text string) (*ConfigBundle, error) {
+	var bundle ConfigBundle
+	if err := json.Unmarshal([]byte(text), &bundle); err != nil {
+		return nil, fmt.Errorf("配置包格式错误: %v", err)
+	}
+	if bundle.Format != ConfigBundleFormat {
+		return nil, fmt.Errorf("不是配置包文件")
+	}
+	if bundle.Version > configBundleVersion {
+		return nil, fmt.Errorf("配置包版本(%d)高于当前程序支持的版本(%d)", bundle.Version, configBundleVersion)
+	}
+	if bundle.SchemaVersion != currentSchemaVersion {
+		return nil, fmt.Errorf("配置包的配置版本(%d)与当前程序(%d)不一致，请在相同版本的程序之间导入导出", bundle.SchemaVersion, currentSchemaVersion)
+	}
+	if len(bundle.Sections) == 0 {
+		return nil, fmt.Errorf("配置包中没有配置项")
+	}
+	all := configSectionKeys()
+	for _, key := range bundle.Sections {
+		if !slices.Contains(all, key) {
+			return nil, fmt.Errorf("配置包中有不支持的配置项: %s", key)
+		}
+		if _, ok := bundle.Config[key]; !ok {
+			return nil, fmt.Errorf("配置包中缺少配置项: %s", key)
+		}
+	}
+
+	checksum, err := configBundleChecksum(&bundle)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(checksum, bundle.Checksum) {
+		return nil, fmt.Errorf("配置包校验失败，文件可能已损坏或被修改")
+	}
+	return &bundle, nil
+}
+
+// applyConfigBundle 将配置包应用到当前配置的副本上，返回导入后的配置，当前配置不变
+func applyConfigBundle(current *SystemConfig, bundle *ConfigBundle, mode string) (*SystemConfig, error) {
+	if mode != ConfigImportMerge && mode != ConfigImportReplace {
+		return nil, fmt.Errorf("不支持的导入方式: %s", mode)
+	}
+	currentValue, err := configJSONValue(current)
+	if err != nil {
+		return nil, err
+	}
+
+	result := cloneSystemConfig(current)
+	fields := systemConfigFields(&result)
+	for _, key := range bundle.Sections {
+		data := []byte(bundle.Config[key])
+		if mode == ConfigImportMerge {
+			var incoming interface{}
+			if err := json.Unmarshal(data, &incoming); err != nil {
+				return nil, fmt.Errorf("配置项%s格式错误: %v", key, err)
+			}
+			if data, err = json.Marshal(mergeJSONValues(currentValue[key], incoming)); err != nil {
+				return nil, fmt.Errorf("合并配置项%s失败: %v", key, err)
+			}
+		}
+
+		if err := decodeConfigField(fields[key], data); err != nil {
+			return nil, fmt.Errorf("配置项%s格式错误: %v", key, err)
+		}
+	}
+	return &result, nil
+}
+
+// mergeJSONValues 合并两个JSON值，冲突时以incoming为准：
+//   - 对象逐个字段合并
+//   - 字符串数组（如别名）取并集，保持原有顺序
+//   - 带名称的对象数组（如号码集合）按名称合并，新增的追加在后
+//   - 其余值以incoming替换
+func mergeJSONValues(base, incoming interface{}) interface{} {
+	if baseMap, ok := base.(map[string]interface{}); ok {
+		if incomingMap, ok := incoming.(map[string]interface{}); ok {
+			merged := make(map[string]interface{}, len(baseMap)+len(incomingMap))
+			for key, value := range baseMap {
+				merged[key] = value
+			}
+			for key, value := range incomingMap {
+				merged[key] = mergeJSONValues(baseMap[key], value)
+			}
+			return merged
+		}
+	}
+
+	if baseStrings, ok := jsonStrings(base); ok {
+		if incomingStrings, ok := jsonStrings(incoming); ok {
+			merged := make([]interface{}, 0, len(baseStrings)+len(incomingStrings))
+			seen := make(map[string]bool)
+			for _, text := range append(baseStrings, incomingStrings...) {
+				if !seen[text] {
+					seen[text] = true
+					merged = append(merged, text)
+				}
+			}
+			return merged
+		}
+	}
+
+	if baseNamed, ok := asNamedJSONList(base); ok {
+		if incomingNamed, ok := asNamedJSONList(incoming); ok {
+			merged := make([]interface{}, 0, len(baseNamed.names)+len(incomingNamed.names))
+			for _, name := range baseNamed.names {
+				if item, ok := incomingNamed.items[name]; ok {
+					merged = append(merged, mergeJSONValues(baseNamed.items[name], item))
+				} else {
+					merged = append(merged, baseNamed.items[name])
+				}
+			}
+			for _, name := range incomingNamed.names {
+				if _, ok := baseNamed.items[name]; !ok {
+					merged = append(merged, incomingNamed.items[name])
+				}
+			}
+			return merged
+		}
+	}
+
+	return incoming
+}
+
+// jsonStrings 值为字符串数组时返回各项
+func jsonStrings(value interface{}) ([]string, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	texts := make([]string, 0, len(list))
+	for _, item := range list {
+		text, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		texts = append(texts, text)
+	}
+	return texts, true
+}
+
+// previewConfigImport 生成导入预览：导入后的变化、校验错误及别名冲突
+func previewConfigImport(current *SystemConfig, bundle *ConfigBundle, mode string) (*ConfigImportPreview, *SystemConfig, error) {
+	result, err := applyConfigBundle(current, bundle, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := diffSystemConfigs(current, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preview := &ConfigImportPreview{
+		Mode:       mode,
+		Sections:   bundle.Sections,
+		ExportedAt: bundle.ExportedAt,
+		Changes:    changes,
+		Errors:     make([]ConfigFieldError, 0),
+		Conflicts:  analyzeAliases(result).Conflicts,
+	}
+	if err := validateSystemConfig(result); err != nil {
+		if validationErr, ok := err.(*ConfigValidationError); ok {
+			preview.Errors = validationErr.Errors
+		} else {
+			preview.Errors = append(preview.Errors, ConfigFieldError{Message: err.Error()})
+		}
+	}
+	return preview, result, nil
+}
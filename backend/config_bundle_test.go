@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfigBundleRoundTrip(t *testing.T) {
+	source := getDefaultSystemConfig()
+	source.BetTypeAliases.ThreeOfThree = append(source.BetTypeAliases.ThreeOfThree, "三三")
+	source.OddsConfig.Special.OddsRatio = 999
+
+	bundle, err := buildConfigBundle(source, []string{"aliases"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"number_sets", "bet_type_aliases", "keyword_aliases"}; !slices.Equal(bundle.Sections, want) {
+		t.Errorf("Sections = %v, want %v", bundle.Sections, want)
+	}
+
+	// 缩进格式不影响校验和
+	data, err := json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseConfigBundle(string(data))
+	if err != nil {
+		t.Fatalf("parseConfigBundle: %v", err)
+	}
+
+	// 内容被修改时校验失败
+	tampered := strings.Replace(string(data), "三三", "三四", 1)
+	if _, err := parseConfigBundle(tampered); err == nil {
+		t.Error("修改后的配置包应校验失败")
+	}
+
+	// 合并：保留本机的别名，加入配置包中的别名，配置包以外的配置项不变
+	target := getDefaultSystemConfig()
+	target.BetTypeAliases.ThreeOfThree = append(target.BetTypeAliases.ThreeOfThree, "叁叁叁")
+	preview, merged, err := previewConfigImport(target, parsed, ConfigImportMerge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Errors) > 0 || len(preview.Conflicts) > 0 {
+		t.Fatalf("preview = %+v", preview)
+	}
+	aliases := merged.BetTypeAliases.ThreeOfThree
+	if !slices.Contains(aliases, "三三") || !slices.Contains(aliases, "叁叁叁") {
+		t.Errorf("合并后三中三别名 = %v", aliases)
+	}
+	if merged.OddsConfig.Special.OddsRatio == 999 {
+		t.Error("只导入别名时赔率不应变化")
+	}
+	if !slices.ContainsFunc(preview.Changes, func(change ConfigChange) bool {
+		return strings.HasPrefix(change.Path, "bet_type_aliases.three_of_three")
+	}) {
+		t.Errorf("Changes = %+v, want 三中三别名的变化", preview.Changes)
+	}
+
+	// 替换：配置项整体替换为配置包中的内容
+	_, replaced, err := previewConfigImport(target, parsed, ConfigImportReplace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(replaced.BetTypeAliases.ThreeOfThree, source.BetTypeAliases.ThreeOfThree) {
+		t.Errorf("替换后三中三别名 = %v, want %v", replaced.BetTypeAliases.ThreeOfThree, source.BetTypeAliases.ThreeOfThree)
+	}
+
+	// 导入到相同的配置没有变化
+	if preview, _, err := previewConfigImport(source, parsed, ConfigImportReplace); err != nil || len(preview.Changes) != 0 {
+		t.Errorf("导入相同配置 Changes = %+v, %v", preview.Changes, err)
+	}
+}
+
+func TestConfigBundleImportConflicts(t *testing.T) {
+	source := getDefaultSystemConfig()
+	source.KeywordAliases.OldMacau = append(source.KeywordAliases.OldMacau, "新澳")
+	bundle, err := buildConfigBundle(source, []string{"keyword_aliases"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview, _, err := previewConfigImport(getDefaultSystemConfig(), bundle, ConfigImportMerge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Conflicts) != 1 || preview.Conflicts[0].Keyword != "新澳" {
+		t.Errorf("Conflicts = %+v, want \"新澳\"", preview.Conflicts)
+	}
+	if _, err := buildConfigBundle(source, []string{"unknown"}); err == nil {
+		t.Error("不支持的配置项应返回错误")
+	}
+}
@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ConfigChange 配置项的一处变化
+type ConfigChange struct {
+	Path   string `json:"path"`   // 配置项路径，如"odds_config.special.odds_ratio"、"number_sets[红波].aliases"
+	Before string `json:"before"` // 变化前的值（JSON），新增时为空
+	After  string `json:"after"`  // 变化后的值（JSON），删除时为空
+}
+
+// diffSystemConfigs 比较两个配置，返回有变化的配置项，不比较结构版本
+func diffSystemConfigs(before, after *SystemConfig) ([]ConfigChange, error) {
+	beforeValue, err := configJSONValue(before)
+	if err != nil {
+		return nil, err
+	}
+	afterValue, err := configJSONValue(after)
+	if err != nil {
+		return nil, err
+	}
+	delete(beforeValue, schemaVersionKey)
+	delete(afterValue, schemaVersionKey)
+
+	changes := make([]ConfigChange, 0)
+	diffJSONValues("", beforeValue, afterValue, &changes)
+	return changes, nil
+}
+
+// configJSONValue 将配置转换为通用JSON值，便于逐项比较、合并
+func configJSONValue(config *SystemConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %v", err)
+	}
+	return value, nil
+}
+
+// diffJSONValues 逐项比较两个JSON值：对象按字段、带名称的对象数组（如号码集合）按名称比较，其余整体比较
+func diffJSONValues(path string, before, after interface{}, changes *[]ConfigChange) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		keys := make(map[string]bool)
+		for key := range beforeMap {
+			keys[key] = true
+		}
+		for key := range afterMap {
+			keys[key] = true
+		}
+		for _, key := range sortedKeys(keys) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffJSONValues(childPath, beforeMap[key], afterMap[key], changes)
+		}
+		return
+	}
+
+	beforeNamed, beforeOK := asNamedJSONList(before)
+	afterNamed, afterOK := asNamedJSONList(after)
+	if beforeOK && afterOK {
+		names := make([]string, 0, len(beforeNamed.names)+len(afterNamed.names))
+		seen := make(map[string]bool)
+		for _, name := range append(append(names, beforeNamed.names...), afterNamed.names...) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			diffJSONValues(fmt.Sprintf("%s[%s]", path, name), beforeNamed.items[name], afterNamed.items[name], changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	*changes = append(*changes, ConfigChange{Path: path, Before: jsonText(before), After: jsonText(after)})
+}
+
+// namedJSONList 按名称索引的对象数组，如号码集合
+type namedJSONList struct {
+	names []string
+	items map[string]interface{}
+}
+
+// asNamedJSONList 数组的每一项都是带唯一"name"字段的对象时，按名称索引
+func asNamedJSONList(value interface{}) (namedJSONList, bool) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return namedJSONList{}, false
+	}
+	named := namedJSONList{names: make([]string, 0, len(list)), items: make(map[string]interface{}, len(list))}
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return namedJSONList{}, false
+		}
+		name, ok := object["name"].(string)
+		if _, exists := named.items[name]; !ok || exists {
+			return namedJSONList{}, false
+		}
+		named.names = append(named.names, name)
+		named.items[name] = object
+	}
+	return named, true
+}
+
+// jsonText JSON值的文本形式，不存在时为空
+func jsonText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
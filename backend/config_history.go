@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ConfigHistoryFileName 配置历史文件，每行一个配置版本，只追加不修改
+const ConfigHistoryFileName = "system_config_history.jsonl"
+
+// configHistoryStore 配置版本历史
+type configHistoryStore struct {
+	mutex    sync.Mutex
+	loaded   bool
+	versions []ConfigVersion
+}
+
+// 全局配置版本历史
+var configHistory = &configHistoryStore{}
+
+// getConfigHistoryFilePath 获取配置历史文件路径，与配置文件位于同一目录
+func getConfigHistoryFilePath() (string, error) {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), ConfigHistoryFileName), nil
+}
+
+// load 读取配置历史文件（只读取一次），格式错误的行记录日志后跳过
+func (h *configHistoryStore) load() error {
+	if h.loaded {
+		return nil
+	}
+	path, err := getConfigHistoryFilePath()
+	if err != nil {
+		return err
+	}
+
+	h.versions = make([]ConfigVersion, 0)
+	err = readJSONLines(path, "配置历史", func(line []byte) error {
+		var version ConfigVersion
+		if err := json.Unmarshal(line, &version); err != nil {
+			return err
+		}
+		h.versions = append(h.versions, version)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	h.loaded = true
+	return nil
+}
+
+// record 记录配置版本：与最新版本相同时不新增，返回当前版本号
+func (h *configHistoryStore) record(config *SystemConfig) (int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := h.load(); err != nil {
+		return 0, err
+	}
+
+	if n := len(h.versions); n > 0 && sameConfig(&h.versions[n-1].Config, config) {
+		return h.versions[n-1].Version, nil
+	}
+
+	version := ConfigVersion{Version: 1, EffectiveAt: time.Now(), Config: cloneSystemConfig(config)}
+	if n := len(h.versions); n > 0 {
+		version.Version = h.versions[n-1].Version + 1
+	}
+	path, err := getConfigHistoryFilePath()
+	if err != nil {
+		return 0, err
+	}
+	if err := appendJSONLine(path, "配置历史", version); err != nil {
+		return 0, err
+	}
+
+	h.versions = append(h.versions, version)
+	safeLogger.AppendLog(fmt.Sprintf("已记录配置版本%d", version.Version))
+	return version.Version, nil
+}
+
+// latest 当前生效的配置版本号，没有历史时为0
+func (h *configHistoryStore) latest() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := h.load(); err != nil || len(h.versions) == 0 {
+		return 0
+	}
+	return h.versions[len(h.versions)-1].Version
+}
+
+// get 返回指定版本的配置
+func (h *configHistoryStore) get(version int) (*ConfigVersion, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	for i := range h.versions {
+		if h.versions[i].Version == version {
+			found := h.versions[i]
+			found.Config = cloneSystemConfig(&found.Config)
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("配置版本%d不存在", version)
+}
+
+// versionAt 返回指定时间生效的配置版本号，早于所有版本时为0
+func (h *configHistoryStore) versionAt(t time.Time) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := h.load(); err != nil {
+		return 0
+	}
+	version := 0
+	for _, v := range h.versions {
+		if v.EffectiveAt.After(t) {
+			break
+		}
+		version = v.Version
+	}
+	return version
+}
+
+// list 所有配置版本概要，按版本号升序
+func (h *configHistoryStore) list() []ConfigVersionInfo {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	infos := make([]ConfigVersionInfo, 0)
+	if err := h.load(); err != nil {
+		safeLogger.AppendLog(err.Error())
+		return infos
+	}
+	for _, v := range h.versions {
+		infos = append(infos, ConfigVersionInfo{Version: v.Version, EffectiveAt: v.EffectiveAt})
+	}
+	return infos
+}
+
+// readJSONLines 逐行读取JSON行文件，文件不存在时不做处理；格式错误的行记录日志后跳过
+// name为文件说明，用于日志及错误信息
+func readJSONLines(path string, name string, handle func(line []byte) error) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取%s失败: %v", name, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := handle(line); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("%s第%d行格式错误，已跳过: %v", name, lineNumber, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取%s失败: %v", name, err)
+	}
+	return nil
+}
+
+// appendJSONLine 向JSON行文件追加一行
+func appendJSONLine(path string, name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("序列化%s失败: %v", name, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("写入%s失败: %v", name, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入%s失败: %v", name, err)
+	}
+	return nil
+}
+
+// sameConfig 比较两个配置的内容是否相同
+func sameConfig(a, b *SystemConfig) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// cloneSystemConfig 深拷贝配置，避免历史版本与内存中的配置共用切片、映射
+func cloneSystemConfig(config *SystemConfig) SystemConfig {
+	var clone SystemConfig
+	data, err := json.Marshal(config)
+	if err == nil {
+		err = json.Unmarshal(data, &clone)
+	}
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("复制配置失败: %v", err))
+		return *config
+	}
+	// 不认识的配置项不参与序列化，单独复制，保存副本时原样写回
+	clone.unknownFields = maps.Clone(config.unknownFields)
+	return clone
+}
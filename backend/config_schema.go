@@ -0,0 +1,168 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 配置文件结构版本
+//   - 1: 生肖、波色、尾数各自为固定结构（zodiac_config、color_config、tail_config）
+//   - 2: 生肖、波色、尾数等统一为号码集合（number_sets）
+//   - 3: 默认玩家标识只匹配行首紧跟冒号的昵称
+//   - 4: 号码集合加入五行（金木水火土）
+//   - 5: 换肖设置补充2036年以后的农历新年日期
+const currentSchemaVersion = 5
+
+// schemaVersionKey 配置文件中记录结构版本的字段
+const schemaVersionKey = "schema_version"
+
+// errConfigUnreadable 配置文件无法解析为JSON对象，只有这种情况才备份原文件并重置为默认配置
+var errConfigUnreadable = errors.New("配置文件无法解析")
+
+// configMigration 将配置文件从上一版本升级到to版本
+// 在原始JSON字段上逐项修改，不认识的字段原样保留
+type configMigration struct {
+	to          int
+	description string
+	migrate     func(raw map[string]json.RawMessage) error
+}
+
+// configMigrations 按版本顺序排列的升级步骤
+var configMigrations = []configMigration{
+	{to: 2, description: "生肖、波色、尾数配置迁移为号码集合", migrate: migrateLegacyNumberSets},
+	{to: 3, description: "默认玩家标识不再匹配冒号前的空白", migrate: migratePlayerHeaderPatterns},
+	{to: 4, description: "号码集合加入五行", migrate: migrateElementNumberSets},
+	{to: 5, description: "补充农历新年日期", migrate: migrateLunarNewYears},
+}
+
+// detectSchemaVersion 配置文件的结构版本，没有记录版本的旧文件按字段推断
+func detectSchemaVersion(raw map[string]json.RawMessage) (int, error) {
+	if value, ok := raw[schemaVersionKey]; ok {
+		var version int
+		if err := json.Unmarshal(value, &version); err != nil {
+			return 0, fmt.Errorf("配置文件版本格式错误: %s", string(value))
+		}
+		return version, nil
+	}
+	if _, ok := raw["number_sets"]; ok {
+		return 2, nil
+	}
+	return 1, nil
+}
+
+// migrateConfig 将配置文件升级到当前版本，返回升级前的版本
+// 版本高于当前程序时不做处理，按当前版本能识别的字段读取
+func migrateConfig(raw map[string]json.RawMessage) (int, error) {
+	fromVersion, err := detectSchemaVersion(raw)
+	if err != nil {
+		return 0, err
+	}
+	if fromVersion > currentSchemaVersion {
+		safeLogger.AppendLog(fmt.Sprintf("配置文件版本(%d)高于当前程序支持的版本(%d)，仅读取能识别的配置项", fromVersion, currentSchemaVersion))
+		return fromVersion, nil
+	}
+
+	for _, migration := range configMigrations {
+		if migration.to <= fromVersion {
+			continue
+		}
+		if err := migration.migrate(raw); err != nil {
+			return fromVersion, fmt.Errorf("配置文件升级到版本%d失败(%s): %v", migration.to, migration.description, err)
+		}
+		raw[schemaVersionKey] = json.RawMessage(strconv.Itoa(migration.to))
+		safeLogger.AppendLog(fmt.Sprintf("配置文件已升级到版本%d: %s", migration.to, migration.description))
+	}
+	return fromVersion, nil
+}
+
+// decodeSystemConfig 解析配置文件内容：先升级到当前版本，再在默认配置上逐项覆盖，返回配置及升级前的版本
+// 文件中缺少的配置项使用默认值，不认识的配置项保存在unknownFields中
+// 有配置项格式错误时整个文件不加载，返回逐项的ConfigValidationError，避免保存时以默认值覆盖原值
+// 无法解析为JSON对象时返回errConfigUnreadable
+func decodeSystemConfig(data []byte) (*SystemConfig, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", errConfigUnreadable, err)
+	}
+	if raw == nil {
+		return nil, 0, fmt.Errorf("%w: 内容不是JSON对象", errConfigUnreadable)
+	}
+
+	fromVersion, err := migrateConfig(raw)
+	if err != nil {
+		return nil, fromVersion, err
+	}
+
+	config := getDefaultSystemConfig()
+	fields := systemConfigFields(config)
+	malformed := make([]ConfigFieldError, 0)
+	for _, key := range sortedKeys(raw) {
+		field, known := fields[key]
+		if !known {
+			if config.unknownFields == nil {
+				config.unknownFields = make(map[string]json.RawMessage)
+			}
+			config.unknownFields[key] = raw[key]
+			continue
+		}
+		if key == schemaVersionKey {
+			continue
+		}
+		if err := decodeConfigField(field, raw[key]); err != nil {
+			malformed = append(malformed, ConfigFieldError{Field: key, Message: fmt.Sprintf("格式错误: %v", err)})
+		}
+	}
+	if len(malformed) > 0 {
+		return nil, fromVersion, &ConfigValidationError{Errors: malformed}
+	}
+	return config, fromVersion, nil
+}
+
+// decodeConfigField 将配置项的JSON内容解析到结构字段，格式错误时字段不变
+func decodeConfigField(field reflect.Value, data []byte) error {
+	value := reflect.New(field.Type())
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return err
+	}
+	field.Set(value.Elem())
+	return nil
+}
+
+// systemConfigFields 配置的JSON字段名到结构字段的映射
+func systemConfigFields(config *SystemConfig) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = value.Field(i)
+		}
+	}
+	return fields
+}
+
+// encodeSystemConfig 序列化配置，不认识的配置项原样写回
+func encodeSystemConfig(config *SystemConfig) ([]byte, error) {
+	if len(config.unknownFields) == 0 {
+		return json.MarshalIndent(config, "", "  ")
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for key, value := range config.unknownFields {
+		if _, exists := raw[key]; !exists {
+			raw[key] = value
+		}
+	}
+	return json.MarshalIndent(raw, "", "  ")
+}
@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSystemConfigMalformedField(t *testing.T) {
+	data := []byte(`{"schema_version": 3, "odds_config": {"special": {"odds_ratio": "40"}}, "future_option": {"enabled": true}}`)
+
+	config, _, err := decodeSystemConfig(data)
+	if config != nil {
+		t.Fatalf("格式错误的配置项不应以默认值加载")
+	}
+	var validationErr *ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want ConfigValidationError", err)
+	}
+	if len(validationErr.Errors) != 1 || validationErr.Errors[0].Field != "odds_config" {
+		t.Errorf("Errors = %+v, want odds_config", validationErr.Errors)
+	}
+}
+
+func TestCloneSystemConfigKeepsUnknownFields(t *testing.T) {
+	data := []byte(`{"schema_version": 3, "future_option": {"enabled": true}}`)
+	config, _, err := decodeSystemConfig(data)
+	if err != nil {
+		t.Fatalf("decodeSystemConfig: %v", err)
+	}
+
+	clone := cloneSystemConfig(config)
+	encoded, err := encodeSystemConfig(&clone)
+	if err != nil {
+		t.Fatalf("encodeSystemConfig: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &raw); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got := strings.Join(strings.Fields(string(raw["future_option"])), ""); got != `{"enabled":true}` {
+		t.Errorf("future_option = %s, want {\"enabled\":true}", got)
+	}
+}
@@ -0,0 +1,309 @@
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigFieldError 配置项校验错误
+type ConfigFieldError struct {
+	Field   string `json:"field"`   // 配置项路径，如"number_sets[3].numbers"、"odds_config.special.odds_ratio"
+	Message string `json:"message"` // 错误说明
+}
+
+// ConfigValidationError 配置校验错误，包含所有不合法的配置项
+type ConfigValidationError struct {
+	Errors []ConfigFieldError `json:"errors"`
+}
+
+// Error 实现error接口，按"配置项: 说明"逐项列出
+func (e *ConfigValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Field+": "+fieldError.Message)
+	}
+	return "配置校验失败: " + strings.Join(messages, "；")
+}
+
+// configValidator 收集配置校验错误
+type configValidator struct {
+	errors []ConfigFieldError
+}
+
+// add 记录一个配置项错误
+func (v *configValidator) add(field string, format string, args ...interface{}) {
+	v.errors = append(v.errors, ConfigFieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err 没有错误时返回nil，否则返回*ConfigValidationError
+func (v *configValidator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ConfigValidationError{Errors: v.errors}
+}
+
+// validateSystemConfig 校验完整的系统配置，加载配置文件时使用
+func validateSystemConfig(config *SystemConfig) error {
+	v := &configValidator{}
+	v.profiles("profiles", config.ActiveProfile, config.Profiles)
+	v.numberSets("number_sets", config.NumberSets)
+	v.zodiacSchedule("zodiac_schedule", config.ZodiacSchedule)
+	v.betTypeAliases("bet_type_aliases", config.BetTypeAliases)
+	v.keywordAliases("keyword_aliases", config.KeywordAliases)
+	v.oddsConfig("odds_config", config.OddsConfig)
+	v.parserOptions("parser_options", config.ParserOptions)
+	v.replyTemplates("reply_templates", config.ReplyTemplates)
+	return v.err()
+}
+
+// validateNumberSets 校验号码集合
+func validateNumberSets(sets []NumberSet) error {
+	v := &configValidator{}
+	v.numberSets("number_sets", sets)
+	return v.err()
+}
+
+// validateZodiacSchedule 校验换肖设置
+func validateZodiacSchedule(schedule ZodiacSchedule) error {
+	v := &configValidator{}
+	v.zodiacSchedule("zodiac_schedule", schedule)
+	return v.err()
+}
+
+// validateBetTypeAliases 校验下注类型别名
+func validateBetTypeAliases(aliases BetTypeAliases) error {
+	v := &configValidator{}
+	v.betTypeAliases("bet_type_aliases", aliases)
+	return v.err()
+}
+
+// validateKeywordAliases 校验关键字别名
+func validateKeywordAliases(aliases KeywordAliases) error {
+	v := &configValidator{}
+	v.keywordAliases("keyword_aliases", aliases)
+	return v.err()
+}
+
+// validateOddsConfig 校验赔率
+func validateOddsConfig(odds OddsConfig) error {
+	v := &configValidator{}
+	v.oddsConfig("odds_config", odds)
+	return v.err()
+}
+
+// validateParserOptions 校验解析选项
+func validateParserOptions(options ParserOptions) error {
+	v := &configValidator{}
+	v.parserOptions("parser_options", options)
+	return v.err()
+}
+
+// validateReplyTemplates 校验回复模板
+func validateReplyTemplates(templates ReplyTemplates) error {
+	v := &configValidator{}
+	v.replyTemplates("reply_templates", templates)
+	return v.err()
+}
+
+// profiles 配置方案：名称不能为空且不重复，各方案的号码集合、别名及赔率与当前配置同样校验
+func (v *configValidator) profiles(field string, active string, profiles []ConfigProfile) {
+	if strings.TrimSpace(active) == "" {
+		v.add("active_profile", "当前配置方案名称不能为空")
+	}
+	names := map[string]bool{active: true}
+	for i, profile := range profiles {
+		profileField := fmt.Sprintf("%s[%d]", field, i)
+		name := strings.TrimSpace(profile.Name)
+		if name == "" {
+			v.add(profileField+".name", "配置方案名称不能为空")
+		} else if names[name] {
+			v.add(profileField+".name", "配置方案名称重复: %s", name)
+		}
+		names[name] = true
+
+		v.numberSets(profileField+".number_sets", profile.NumberSets)
+		v.betTypeAliases(profileField+".bet_type_aliases", profile.BetTypeAliases)
+		v.keywordAliases(profileField+".keyword_aliases", profile.KeywordAliases)
+		v.oddsConfig(profileField+".odds_config", profile.OddsConfig)
+	}
+}
+
+// numberSets 号码集合：名称必填，别名不能为空，名称与别名不重复，号码在1-49之间且不重复
+// 生肖、波色各自须覆盖1-49且每个号码只属于一个集合
+func (v *configValidator) numberSets(field string, sets []NumberSet) {
+	owners := make(map[string]string)
+	for i, set := range sets {
+		setField := fmt.Sprintf("%s[%d]", field, i)
+		name := strings.TrimSpace(set.Name)
+		if name == "" {
+			v.add(setField+".name", "号码集合名称不能为空")
+		}
+		if len(set.Numbers) == 0 {
+			v.add(setField+".numbers", "号码集合\"%s\"没有号码", name)
+		}
+		seen := make(map[int]bool)
+		for _, number := range set.Numbers {
+			if !isValidLotteryNumber(number) {
+				v.add(setField+".numbers", "号码集合\"%s\"中号码%d超出范围(%d-%d)", name, number, MinLotteryNumber, MaxLotteryNumber)
+			} else if seen[number] {
+				v.add(setField+".numbers", "号码集合\"%s\"中号码%d重复", name, number)
+			}
+			seen[number] = true
+		}
+		for j, alias := range set.Aliases {
+			if strings.TrimSpace(alias) == "" {
+				v.add(fmt.Sprintf("%s.aliases[%d]", setField, j), "号码集合\"%s\"的别名不能为空", name)
+			}
+		}
+
+		for _, keyword := range append([]string{name}, set.Aliases...) {
+			keyword = normalizeText(strings.TrimSpace(keyword))
+			if keyword == "" {
+				continue
+			}
+			if owner, ok := owners[keyword]; ok {
+				v.add(setField, "\"%s\"同时是号码集合\"%s\"和\"%s\"的名称或别名", keyword, owner, name)
+				continue
+			}
+			owners[keyword] = name
+		}
+	}
+
+	for _, category := range []string{NumberSetZodiac, NumberSetColor} {
+		v.numberPartition(field, sets, category)
+	}
+}
+
+// numberPartition 检查分类中的集合是否恰好划分1-49：每个号码只属于一个集合，且没有遗漏
+// 分类中没有集合时不检查
+func (v *configValidator) numberPartition(field string, sets []NumberSet, category string) {
+	owners := make(map[int]string)
+	found := false
+	for i, set := range sets {
+		if set.Category != category {
+			continue
+		}
+		found = true
+		for _, number := range set.Numbers {
+			if owner, ok := owners[number]; ok && owner != set.Name {
+				v.add(fmt.Sprintf("%s[%d].numbers", field, i), "号码%d同时属于%s\"%s\"和\"%s\"", number, category, owner, set.Name)
+				continue
+			}
+			owners[number] = set.Name
+		}
+	}
+	if !found {
+		return
+	}
+
+	missing := make([]string, 0)
+	for number := MinLotteryNumber; number <= MaxLotteryNumber; number++ {
+		if _, ok := owners[number]; !ok {
+			missing = append(missing, strconv.Itoa(number))
+		}
+	}
+	if len(missing) > 0 {
+		v.add(field, "号码%s不属于任何%s", strings.Join(missing, "、"), category)
+	}
+}
+
+// zodiacSchedule 换肖设置：key为年份，日期须为该年的"2006-01-02"格式
+// 自动换肖时须配置当年及下一年的新年日期，未配置的年份无法确定换肖日期
+func (v *configValidator) zodiacSchedule(field string, schedule ZodiacSchedule) {
+	if schedule.AutoUpdate {
+		year := time.Now().Year()
+		for _, required := range []int{year, year + 1} {
+			if _, ok := schedule.NewYearDates[strconv.Itoa(required)]; !ok {
+				v.add(field+".new_year_dates", "未配置%d年的农历新年日期，自动换肖需要当年及下一年的新年日期", required)
+			}
+		}
+	}
+
+	// 按年份顺序校验，错误信息的顺序不随映射遍历顺序变化
+	for _, year := range sortedKeys(schedule.NewYearDates) {
+		text := schedule.NewYearDates[year]
+		dateField := fmt.Sprintf("%s.new_year_dates.%s", field, year)
+		yearNumber, err := strconv.Atoi(year)
+		if err != nil {
+			v.add(dateField, "新年日期的年份格式错误: %s", year)
+			continue
+		}
+		date, err := newYearDate(yearNumber, schedule.NewYearDates)
+		if err != nil {
+			v.add(dateField, "%v", err)
+			continue
+		}
+		if date.Year() != yearNumber {
+			v.add(dateField, "%s年的新年日期不在该年内: %s", year, text)
+		}
+	}
+}
+
+// betTypeAliases 下注类型别名：每种下注类型至少一个别名，别名不能为空
+func (v *configValidator) betTypeAliases(field string, aliases BetTypeAliases) {
+	v.aliasList(field+".three_of_three", "三中三", aliases.ThreeOfThree)
+	v.aliasList(field+".three_of_two", "三中二", aliases.ThreeOfTwo)
+	v.aliasList(field+".two_of_two", "二中二", aliases.TwoOfTwo)
+	v.aliasList(field+".special", "特碰", aliases.Special)
+}
+
+// keywordAliases 关键字别名：每个关键字至少一个别名，别名不能为空
+func (v *configValidator) keywordAliases(field string, aliases KeywordAliases) {
+	v.aliasList(field+".new_macau", "新澳", aliases.NewMacau)
+	v.aliasList(field+".old_macau", "老澳", aliases.OldMacau)
+	v.aliasList(field+".hong_kong", "香港", aliases.HongKong)
+	v.aliasList(field+".complex", "复式", aliases.Complex)
+	v.aliasList(field+".drag", "拖", aliases.Drag)
+	v.aliasList(field+".each", "各", aliases.Each)
+	v.aliasList(field+".per_group", "每组", aliases.PerGroup)
+}
+
+// aliasList 别名列表至少一项，且每项不能为空（空别名会匹配任意文字）
+func (v *configValidator) aliasList(field string, name string, aliases []string) {
+	if len(aliases) == 0 {
+		v.add(field, "%s至少需要一个别名", name)
+	}
+	for i, alias := range aliases {
+		if strings.TrimSpace(alias) == "" {
+			v.add(fmt.Sprintf("%s[%d]", field, i), "%s的别名不能为空", name)
+		}
+	}
+}
+
+// oddsConfig 赔率须大于0，回水率在0到1之间
+func (v *configValidator) oddsConfig(field string, odds OddsConfig) {
+	v.odds(field+".three_of_three", "三中三", odds.ThreeOfThree.OddsRatio, odds.ThreeOfThree.Rebate)
+	v.odds(field+".three_of_two.hit_two_odds", "三中二中二", odds.ThreeOfTwo.HitTwoOdds.OddsRatio, odds.ThreeOfTwo.HitTwoOdds.Rebate)
+	v.odds(field+".three_of_two.hit_three_odds", "三中二中三", odds.ThreeOfTwo.HitThreeOdds.OddsRatio, odds.ThreeOfTwo.HitThreeOdds.Rebate)
+	v.odds(field+".two_of_two", "二中二", odds.TwoOfTwo.OddsRatio, odds.TwoOfTwo.Rebate)
+	v.odds(field+".special", "特碰", odds.Special.OddsRatio, odds.Special.Rebate)
+}
+
+// odds 单项赔率及回水率
+func (v *configValidator) odds(field string, name string, ratio float64, rebate float64) {
+	if ratio <= 0 {
+		v.add(field+".odds_ratio", "%s赔率须大于0: %g", name, ratio)
+	}
+	if rebate < 0 || rebate >= 1 {
+		v.add(field+".rebate", "%s回水率须在0到1之间: %g", name, rebate)
+	}
+}
+
+// parserOptions 解析选项：玩家标识须为含昵称分组的正则表达式
+func (v *configValidator) parserOptions(field string, options ParserOptions) {
+	for i, pattern := range options.PlayerHeaderPatterns {
+		if _, err := compilePlayerHeaderPatterns([]string{pattern}); err != nil {
+			v.add(fmt.Sprintf("%s.player_header_patterns[%d]", field, i), "%v", err)
+		}
+	}
+}
+
+// replyTemplates 回复模板须为合法的模板语法
+func (v *configValidator) replyTemplates(field string, templates ReplyTemplates) {
+	if err := templates.validate(); err != nil {
+		v.add(field, "%v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateZodiacScheduleErrorOrder(t *testing.T) {
+	schedule := ZodiacSchedule{NewYearDates: map[string]string{
+		"2031": "2031/01/23",
+		"2027": "2028-02-06",
+		"abc":  "2026-02-17",
+		"2029": "2029-02-13",
+		"2030": "bad",
+	}}
+	want := []string{
+		"zodiac_schedule.new_year_dates.2027",
+		"zodiac_schedule.new_year_dates.2030",
+		"zodiac_schedule.new_year_dates.2031",
+		"zodiac_schedule.new_year_dates.abc",
+	}
+	for i := 0; i < 20; i++ {
+		err, ok := validateZodiacSchedule(schedule).(*ConfigValidationError)
+		if !ok || len(err.Errors) != len(want) {
+			t.Fatalf("validateZodiacSchedule = %v, want %d个错误", err, len(want))
+		}
+		for j, fieldErr := range err.Errors {
+			if fieldErr.Field != want[j] {
+				t.Fatalf("第%d个错误 = %s, want %s", j+1, fieldErr.Field, want[j])
+			}
+		}
+	}
+}
+
+func TestValidateSystemConfig(t *testing.T) {
+	if err := validateSystemConfig(getDefaultSystemConfig()); err != nil {
+		t.Fatalf("默认配置校验失败: %v", err)
+	}
+
+	setNumbers := func(config *SystemConfig, name string, numbers []int) {
+		for i := range config.NumberSets {
+			if config.NumberSets[i].Name == name {
+				config.NumberSets[i].Numbers = numbers
+			}
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(config *SystemConfig)
+		field  string // 第一个错误的配置项
+	}{
+		{"号码超出范围", func(c *SystemConfig) { setNumbers(c, "0尾", []int{10, 20, 30, 40, 50}) }, "number_sets[%d].numbers"},
+		{"号码同时属于两个波色", func(c *SystemConfig) {
+			for _, set := range c.NumberSets {
+				if set.Name == "蓝波" {
+					setNumbers(c, "红波", append(slices.Clone(set.Numbers[:1]), 1, 2))
+				}
+			}
+		}, "number_sets[%d].numbers"},
+		{"号码不属于任何生肖", func(c *SystemConfig) {
+			for _, set := range c.NumberSets {
+				if set.Name == "鼠" {
+					setNumbers(c, "鼠", set.Numbers[1:])
+				}
+			}
+		}, "number_sets"},
+		{"赔率为负数", func(c *SystemConfig) { c.OddsConfig.Special.OddsRatio = -1 }, "odds_config.special.odds_ratio"},
+		{"别名为空", func(c *SystemConfig) { c.BetTypeAliases.TwoOfTwo = append(c.BetTypeAliases.TwoOfTwo, " ") }, "bet_type_aliases.two_of_two[%d]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := getDefaultSystemConfig()
+			tt.modify(config)
+			err, ok := validateSystemConfig(config).(*ConfigValidationError)
+			if !ok || len(err.Errors) == 0 {
+				t.Fatalf("validateSystemConfig = %v, want 校验错误", err)
+			}
+			if !configFieldMatches(err.Errors[0].Field, tt.field) {
+				t.Errorf("错误的配置项 = %s (%s), want %s", err.Errors[0].Field, err.Errors[0].Message, tt.field)
+			}
+		})
+	}
+}
+
+// configFieldMatches 比较配置项路径，pattern中的%d匹配任意下标
+func configFieldMatches(field, pattern string) bool {
+	var index int
+	if !strings.Contains(pattern, "%d") {
+		return field == pattern
+	}
+	_, err := fmt.Sscanf(field, pattern, &index)
+	return err == nil && fmt.Sprintf(pattern, index) == field
+}
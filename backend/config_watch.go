@@ -0,0 +1,183 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// configReloadedEvent 配置文件被外部修改并重新加载后发给前端的事件，数据为新的配置版本号
+const configReloadedEvent = "config:reloaded"
+
+// configRejectedEvent 配置文件被外部修改但内容不合法时发给前端的事件，数据为ConfigReloadError
+const configRejectedEvent = "config:rejected"
+
+// configWatchInterval 检查配置文件是否被修改的间隔
+const configWatchInterval = 2 * time.Second
+
+// configFileOperator 外部修改配置文件时审计日志中的操作员
+const configFileOperator = "配置文件"
+
+// ConfigReloadError 配置文件被外部修改但不能加载的原因
+type ConfigReloadError struct {
+	Message string             `json:"message"` // 错误说明
+	Errors  []ConfigFieldError `json:"errors"`  // 不合法的配置项，格式错误等无法逐项校验时为空
+}
+
+// configFileWatcher 按修改时间、大小及内容检查配置文件是否变化
+type configFileWatcher struct {
+	path    string
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// newConfigFileWatcher 以配置文件的当前内容为起点
+func newConfigFileWatcher(path string) *configFileWatcher {
+	w := &configFileWatcher{path: path}
+	w.changed()
+	return w
+}
+
+// changed 配置文件内容变化时返回新内容；文件不存在、只是修改时间变化或内容未变时返回nil
+func (w *configFileWatcher) changed() ([]byte, error) {
+	info, err := os.Stat(w.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil, nil
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return nil, err
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	sum := sha256.Sum256(data)
+	if sum == w.sum {
+		return nil, nil
+	}
+	w.sum = sum
+	return data, nil
+}
+
+// watchConfigFile 定时检查配置文件，被外部修改时校验并重新加载，程序关闭时停止
+// 本程序保存配置与检查配置文件互斥，保存后的文件与内存中的配置相同，不会重复加载
+func (a *App) watchConfigFile() {
+	defer recoverWithLog("watchConfigFile")
+
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("监视配置文件失败: %v", err))
+		return
+	}
+	watcher := newConfigFileWatcher(configPath)
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.shutdownChan:
+			return
+		case <-ticker.C:
+			a.checkConfigFile(watcher)
+		}
+	}
+}
+
+// checkConfigFile 检查配置文件并加载被外部修改的内容
+// 读取和加载期间持有配置保存锁，避免读到保存前的旧文件后把刚保存的配置改回去
+func (a *App) checkConfigFile(watcher *configFileWatcher) {
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+
+	data, err := watcher.changed()
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("检查配置文件失败: %v", err))
+		return
+	}
+	if data != nil {
+		a.applyConfigFile(data)
+	}
+}
+
+// ReloadSystemConfig 重新读取配置文件，内容不合法时保持当前配置并返回错误
+func (a *App) ReloadSystemConfig() error {
+	defer recoverWithLog("ReloadSystemConfig")
+
+	a.saveMutex.Lock()
+	defer a.saveMutex.Unlock()
+
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	return a.applyConfigFile(data)
+}
+
+// applyConfigFile 加载配置文件内容并通知前端，不合法时保持当前配置（调用方持有配置保存锁）
+func (a *App) applyConfigFile(data []byte) error {
+	reloaded, err := a.reloadConfigData(data)
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("配置文件已被修改但不能加载，继续使用当前配置: %v", err))
+		reloadErr := ConfigReloadError{Message: err.Error(), Errors: make([]ConfigFieldError, 0)}
+		var validationErr *ConfigValidationError
+		if errors.As(err, &validationErr) {
+			reloadErr.Errors = validationErr.Errors
+		}
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx, configRejectedEvent, reloadErr)
+		}
+		return err
+	}
+	if reloaded {
+		version := configHistory.latest()
+		safeLogger.AppendLog(fmt.Sprintf("配置文件已被修改，已重新加载(配置版本%d)", version))
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx, configReloadedEvent, version)
+		}
+	}
+	return nil
+}
+
+// reloadConfigData 解析并校验配置文件内容，与内存中的配置不同时替换，返回是否替换或修正了启动时不能加载的配置文件
+// 有配置项格式错误或不合法时整个文件不加载
+func (a *App) reloadConfigData(data []byte) (bool, error) {
+	config, _, err := decodeSystemConfig(data)
+	if err != nil {
+		return false, err
+	}
+	if err := validateSystemConfig(config); err != nil {
+		return false, err
+	}
+
+	a.mutex.Lock()
+	// 启动时不能加载的配置文件已修正，之后允许保存；内容与暂用的默认配置相同时也通知前端
+	fixed := a.configLoadErr != nil
+	a.configLoadErr = nil
+	if sameConfig(a.systemConfig, config) {
+		a.systemConfig.unknownFields = config.unknownFields
+		a.mutex.Unlock()
+		return fixed, nil
+	}
+	before := cloneSystemConfig(a.systemConfig)
+	*a.systemConfig = *config
+	a.mutex.Unlock()
+
+	if _, err := configHistory.record(config); err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("记录配置版本失败: %v", err))
+	}
+	a.recordConfigAudit("外部修改配置文件", configFileOperator, &before, 0)
+	return true, nil
+}
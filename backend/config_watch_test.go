@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigFileWatcherChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	watcher := newConfigFileWatcher(path)
+
+	// 文件不存在时不算变化
+	if data, err := watcher.changed(); data != nil || err != nil {
+		t.Fatalf("文件不存在: changed = %q, %v", data, err)
+	}
+
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+
+	write(`{"a":1}`, start)
+	if data, err := watcher.changed(); string(data) != `{"a":1}` || err != nil {
+		t.Fatalf("新建文件: changed = %q, %v", data, err)
+	}
+	if data, _ := watcher.changed(); data != nil {
+		t.Errorf("文件未变: changed = %q, want nil", data)
+	}
+
+	// 只修改时间变化、内容不变时不算变化
+	write(`{"a":1}`, start.Add(time.Minute))
+	if data, _ := watcher.changed(); data != nil {
+		t.Errorf("只修改时间变化: changed = %q, want nil", data)
+	}
+
+	// 大小相同、修改时间变化的新内容
+	write(`{"a":2}`, start.Add(2*time.Minute))
+	if data, _ := watcher.changed(); string(data) != `{"a":2}` {
+		t.Errorf("内容变化: changed = %q, want {\"a\":2}", data)
+	}
+
+	// 以已有文件为起点的监视器不把当前内容当作变化
+	if data, _ := newConfigFileWatcher(path).changed(); data != nil {
+		t.Errorf("新监视器: changed = %q, want nil", data)
+	}
+}
+
+func TestReloadConfigDataKeepsConfigOnError(t *testing.T) {
+	app := &App{systemConfig: getDefaultSystemConfig()}
+	before := cloneSystemConfig(app.systemConfig)
+
+	invalid := getDefaultSystemConfig()
+	for i := range invalid.NumberSets {
+		if invalid.NumberSets[i].Name == "0尾" {
+			invalid.NumberSets[i].Numbers = []int{10, 20, 30, 40, 50}
+		}
+	}
+	invalidData, err := encodeSystemConfig(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		validation bool // 是否为逐项校验的错误
+	}{
+		{"格式错误", []byte(`{"number_sets": [`), false},
+		{"号码超出范围", invalidData, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloaded, err := app.reloadConfigData(tt.data)
+			if err == nil || reloaded {
+				t.Fatalf("reloadConfigData = %v, %v, want 错误", reloaded, err)
+			}
+			var validationErr *ConfigValidationError
+			if errors.As(err, &validationErr) != tt.validation {
+				t.Errorf("错误类型 = %T, 逐项校验 = %v", err, tt.validation)
+			}
+			if !sameConfig(app.systemConfig, &before) {
+				t.Errorf("加载失败后配置被修改")
+			}
+		})
+	}
+}
+
+func TestReloadConfigDataSameContent(t *testing.T) {
+	app := &App{systemConfig: getDefaultSystemConfig()}
+	data, err := encodeSystemConfig(getDefaultSystemConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 内容与内存中的配置相同时不替换
+	if reloaded, err := app.reloadConfigData(data); reloaded || err != nil {
+		t.Errorf("内容相同: reloadConfigData = %v, %v, want false, nil", reloaded, err)
+	}
+
+	// 启动时不能加载的配置文件被修正后，即使内容与暂用的默认配置相同也通知前端
+	app.configLoadErr = errors.New("配置文件格式错误")
+	if reloaded, err := app.reloadConfigData(data); !reloaded || err != nil {
+		t.Errorf("修正配置文件: reloadConfigData = %v, %v, want true, nil", reloaded, err)
+	}
+	if app.configLoadErr != nil {
+		t.Errorf("修正后configLoadErr = %v, want nil", app.configLoadErr)
+	}
+}
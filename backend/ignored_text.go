@@ -0,0 +1,33 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// collectIgnoredText 收集下注中无法识别的文字
+func collectIgnoredText(tokens []Token) []string {
+	ignored := make([]string, 0)
+	for _, token := range tokens {
+		if token.Type == TokenUnknown {
+			ignored = append(ignored, token.Text)
+		}
+	}
+	return ignored
+}
+
+// checkIgnoredText 在下注上记录被忽略的文字
+// 严格模式下视为错误，否则作为提示信息供操作员确认
+func (p *IntelligentBetParser) checkIgnoredText(bet *SingleBetParsing, ignored []string) {
+	bet.IgnoredText = ignored
+	if len(ignored) == 0 {
+		return
+	}
+
+	if p.config.StrictUnknownText {
+		bet.HasError = true
+		bet.ErrorMessage = append(bet.ErrorMessage, fmt.Sprintf("存在无法识别的内容: %s，请修改后重新提交", strings.Join(ignored, ", ")))
+		return
+	}
+	bet.Warnings = append(bet.Warnings, fmt.Sprintf("已忽略: %s", strings.Join(ignored, ", ")))
+}
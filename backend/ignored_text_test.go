@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckIgnoredText(t *testing.T) {
+	tests := []struct {
+		input   string
+		ignored []string
+	}{
+		{"三中三1.9.38 各20块 谢谢", []string{"块", "谢谢"}},
+		{"三中三1.9.38各20 老板好", []string{"老板好"}},
+		{"三中三1.9.38各20", []string{}},
+	}
+
+	config := newParserConfig(getDefaultSystemConfig(), 0, time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local))
+	strictConfig := config
+	strictConfig.StrictUnknownText = true
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := NewIntelligentBetParser(config).ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError {
+				t.Fatalf("解析失败: %v", result.ErrorMessages)
+			}
+			bet := result.ParsedBets[0]
+			if !slices.Equal(bet.IgnoredText, tt.ignored) {
+				t.Errorf("IgnoredText = %q, want %q", bet.IgnoredText, tt.ignored)
+			}
+			if wantWarnings := min(len(tt.ignored), 1); len(bet.Warnings) != wantWarnings {
+				t.Errorf("提示信息 = %q, want %d条", bet.Warnings, wantWarnings)
+			}
+
+			// 严格模式下无法识别的文字为错误
+			strict := NewIntelligentBetParser(strictConfig).ParseBetString(BetParseRequest{Input: tt.input})
+			if strict.HasError != (len(tt.ignored) > 0) {
+				t.Errorf("严格模式HasError = %v, errors: %v", strict.HasError, strict.ErrorMessages)
+			}
+		})
+	}
+}
@@ -5,13 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
-	"slices"
-	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
-	"unicode"
 
 	"github.com/shopspring/decimal"
 )
@@ -20,11 +17,17 @@ var roundIDCounter int64
 
 // IntelligentBetParser 智能下注解析器
 type IntelligentBetParser struct {
-	config IntelligentBetParserConfig
+	config        IntelligentBetParserConfig
+	trie          *keywordTrie     // 由配置构建的关键词前缀树
+	playerHeaders []*regexp.Regexp // 玩家标识
 }
 
 func NewIntelligentBetParser(config IntelligentBetParserConfig) *IntelligentBetParser {
-	return &IntelligentBetParser{config: config}
+	playerHeaders, err := compilePlayerHeaderPatterns(config.PlayerHeaderPatterns)
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("玩家标识配置无效，不按玩家拆分: %v", err))
+	}
+	return &IntelligentBetParser{config: config, trie: newKeywordTrie(config), playerHeaders: playerHeaders}
 }
 
 // ParseBetString 智能解析下注字符串
@@ -33,10 +36,15 @@ func (p *IntelligentBetParser) ParseBetString(request BetParseRequest) BetParsin
 	roundID := strconv.FormatInt(atomic.AddInt64(&roundIDCounter, 1), 10)
 
 	result := BetParsingResult{
-		RoundID:       roundID,
-		OriginalText:  request.Input,
-		ParseTime:     startTime,
-		ErrorMessages: make([]string, 0),
+		RoundID:         roundID,
+		OriginalText:    request.Input,
+		ParseTime:       startTime,
+		ErrorMessages:   make([]string, 0),
+		WarningMessages: make([]string, 0),
+		Ambiguities:     make([]BetAmbiguity, 0),
+		ZodiacTable:     p.config.ZodiacTable,
+		ConfigVersion:   p.config.ConfigVersion,
+		Profile:         p.config.Profile,
 	}
 
 	if strings.TrimSpace(request.Input) == "" {
@@ -44,420 +52,194 @@ func (p *IntelligentBetParser) ParseBetString(request BetParseRequest) BetParsin
 		result.ErrorMessages = append(result.ErrorMessages, "输入为空")
 		return result
 	}
-	processedText := request.Input
-	safeLogger.AppendLog(fmt.Sprintf("开始智能解析: %s", processedText))
-	// 1. 替换关键词（生肖、颜色、尾数）
-	processedText = p.replaceKeywords(processedText)
-	safeLogger.AppendLog(fmt.Sprintf("替换关键词: %s", processedText))
-	// 2. 字符串预处理
-	processedText = p.preprocessText(processedText)
-	safeLogger.AppendLog(fmt.Sprintf("字符串预处理: %s", processedText))
-
-	// 3. 移除非关键字的所有中文记清理关键词后面的空格
-	processedText = p.removeChineseChars(processedText)
-	safeLogger.AppendLog(fmt.Sprintf("移除非关键字的所有中文及替换莫名的空格: %s", processedText))
+	safeLogger.AppendLog(fmt.Sprintf("开始智能解析: %s", request.Input))
 
-	// 4. 分割为多笔下注
-	betSegments := p.segmentBets(processedText)
-	safeLogger.AppendLog(fmt.Sprintf("分割为多笔下注: %s", betSegments))
+	// 1. 去除微信消息噪声：引用回复、@提及、表情代码等
+	input, removed := p.stripWeChatNoise(request.Input)
+	if len(removed) > 0 {
+		safeLogger.AppendLog(fmt.Sprintf("已去除微信消息内容: %s", strings.Join(removed, ", ")))
+	}
+	if strings.TrimSpace(input) == "" {
+		result.HasError = true
+		result.ErrorMessages = append(result.ErrorMessages, "去除微信消息内容后输入为空")
+		return result
+	}
 
-	// 5. 解析每笔下注
-	parsedBets := make([]SingleBetParsing, 0)
-	context := &BetContext{inheritedLotteries: make([]string, 0)}
+	// 2. 按玩家标识拆分，各玩家的下注分别解析，体彩及下注类型不跨玩家继承
+	blocks := p.splitPlayerBlocks(input)
+	if len(blocks) == 1 && blocks[0].Player == "" {
+		p.parseRound(&result, input, request.InterpretationChoices)
+		result.ParseTime = time.Now()
+		return result
+	}
+	safeLogger.AppendLog(fmt.Sprintf("按玩家拆分为%d段", len(blocks)))
 
-	for i, segment := range betSegments {
-		betID := fmt.Sprintf("%s_bet_%d", roundID, i+1)
-		parsed := p.parseSingleBet(betID, segment, context)
-		parseJson, _ := json.Marshal(parsed)
-		safeLogger.AppendLog(fmt.Sprintf("解析每笔下注: %s", string(parseJson)))
-		parsedBets = append(parsedBets, parsed)
+	result.ParsedBets = make([]SingleBetParsing, 0)
+	result.PlayerRounds = make([]BetParsingResult, 0, len(blocks))
+	for _, block := range blocks {
+		player := block.Player
+		if player == "" {
+			player = "未标明玩家"
+		}
+		if block.Text == "" {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("%s: 没有下注内容", player))
+			continue
+		}
 
-		// 更新体彩继承状态
-		if len(parsed.LotteryBets) > 0 {
-			context.inheritedLotteries = make([]string, 0)
-			for lotteryType := range parsed.LotteryBets {
-				context.inheritedLotteries = append(context.inheritedLotteries, lotteryType)
+		round := BetParsingResult{
+			RoundID:         strconv.FormatInt(atomic.AddInt64(&roundIDCounter, 1), 10),
+			Player:          block.Player,
+			OriginalText:    block.Text,
+			ParseTime:       startTime,
+			ErrorMessages:   make([]string, 0),
+			WarningMessages: make([]string, 0),
+			Ambiguities:     make([]BetAmbiguity, 0),
+			ZodiacTable:     p.config.ZodiacTable,
+			ConfigVersion:   p.config.ConfigVersion,
+			Profile:         p.config.Profile,
+		}
+		// 理解方式的选择按整段输入中的下注序号传入，换算为该玩家内的序号
+		offset := len(result.ParsedBets)
+		choices := make(map[int]int)
+		for index, choice := range request.InterpretationChoices {
+			if index > offset {
+				choices[index-offset] = choice
 			}
 		}
-	}
-
-	result.ParsedBets = parsedBets
-
-	// 6. 生成统计信息
-	result.RoundStatistics = p.generateRoundStatistics(parsedBets)
+		p.parseRound(&round, block.Text, choices)
+		for i := range round.ParsedBets {
+			round.ParsedBets[i].Player = block.Player
+		}
+		round.ParseTime = time.Now()
+		result.PlayerRounds = append(result.PlayerRounds, round)
 
-	// 7. 检查错误
-	for _, bet := range parsedBets {
-		if bet.HasError {
+		// 汇总到整段结果
+		result.ParsedBets = append(result.ParsedBets, round.ParsedBets...)
+		for _, ambiguity := range round.Ambiguities {
+			ambiguity.BetIndex += offset
+			result.Ambiguities = append(result.Ambiguities, ambiguity)
+		}
+		if round.HasError {
 			result.HasError = true
-			result.ErrorMessages = append(result.ErrorMessages, bet.ErrorMessage...)
+		}
+		for _, message := range round.ErrorMessages {
+			result.ErrorMessages = append(result.ErrorMessages, fmt.Sprintf("%s: %s", player, message))
+		}
+		for _, message := range round.WarningMessages {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("%s: %s", player, message))
 		}
 	}
+	result.RoundStatistics = p.generateRoundStatistics(result.ParsedBets)
 
 	result.ParseTime = time.Now()
 	return result
 }
 
-// preprocessText 字符串预处理
-func (p *IntelligentBetParser) preprocessText(text string) string {
-	// 1. 换行符替换为空格
-	text = strings.ReplaceAll(text, "\n", " ")
-	text = strings.ReplaceAll(text, "\r", " ")
-
-	// 2. 智能处理分隔符
-	text = p.smartReplaceSeparators(text)
-
-
-	// 定义你想要替换的符号字符串
-	symbolsToReplace := "【】[]{}“‘”’" // 可以在这里添加你需要的任何符号
-	// 将字符串中的所有元字符转义，然后作为正则表达式的模式
-	pattern := "[" + regexp.QuoteMeta(symbolsToReplace) + "]+"
-
-	invalidCharsRe := regexp.MustCompile(pattern)
-	text = invalidCharsRe.ReplaceAllString(text, " ")
+// parseRound 解析一轮下注：词法分析、分段、逐笔解析并生成统计信息
+// choices key: 本轮内的下注序号（从1开始） value: 理解方式下标
+func (p *IntelligentBetParser) parseRound(result *BetParsingResult, input string, choices map[int]int) {
+	roundID := result.RoundID
 
-	// 3. 处理分隔符后的空格：保留分隔符，移除空格
-	spaceAfterSeparatorRe := regexp.MustCompile(`([./\\\-=:,，、+。*])\s+`)
-	text = spaceAfterSeparatorRe.ReplaceAllString(text, "$1")
+	// 1. 词法分析：按最长匹配识别关键词、号码、金额及声明
+	tokens := p.tokenize(input)
+	safeLogger.AppendLog(fmt.Sprintf("词法分析: %s", formatTokens(tokens)))
 
-	// 4. 清理多余空格
-	spaceRe := regexp.MustCompile(`\s+`)
-	text = spaceRe.ReplaceAllString(text, " ")
+	// 2. 按金额分割为多笔下注
+	betSegments := p.segmentBets(tokens)
 
-	return strings.TrimSpace(text)
-}
-
-// smartReplaceSeparators 智能替换分隔符
-func (p *IntelligentBetParser) smartReplaceSeparators(text string) string {
-	// 定义所有分隔符（同一级别）
-	separators := map[rune]bool{
-		'.': true, '/': true, '\\': true, '-': true, '=': true, ':': true,
-		',': true, '，': true, '、': true, '+': true, '。': true, '*': true,
-	}
-	
-	result := make([]rune, 0, len(text))
-	runes := []rune(text)
-	
-	for i := 0; i < len(runes); i++ {
-		char := runes[i]
-		
-		// 如果是分隔符
-		if separators[char] {
-			// 处理连续分隔符
-			separatorGroup := []rune{char}
-			j := i + 1
-			
-			// 收集连续的分隔符
-			for j < len(runes) && separators[runes[j]] {
-				separatorGroup = append(separatorGroup, runes[j])
-				j++
-			}
-			
-			// 判断用什么替换
-			replacement := p.decideSeparatorReplacement(runes, i, separatorGroup)
-			result = append(result, []rune(replacement)...)
-			
-			// 跳过已处理的分隔符
-			i = j - 1
-		} else {
-			result = append(result, char)
-		}
+	// 3. 拆分下注片段及金额回填：未标明金额的下注沿用后续金额
+	segments := p.backfillSegmentAmounts(betSegments)
+	segmentTexts := make([]string, len(segments))
+	for i, segment := range segments {
+		segmentTexts[i] = segment.Text
 	}
-	
-	return string(result)
-}
-
-// decideSeparatorReplacement 决定分隔符的替换方式
-func (p *IntelligentBetParser) decideSeparatorReplacement(runes []rune, startPos int, separatorGroup []rune) string {
-	// 1. 检查前后是否为数字
-	prevIsDigit := false
-	if startPos > 0 && unicode.IsDigit(runes[startPos-1]) {
-		prevIsDigit = true
-	}
-	
-	nextIsDigit := false
-	nextPos := startPos + len(separatorGroup)
-	if nextPos < len(runes) && unicode.IsDigit(runes[nextPos]) {
-		nextIsDigit = true
-	}
-	
-	// 2. 如果前后都是数字，且分隔符组是同一类型，替换为"-"
-	if prevIsDigit && nextIsDigit && p.isSameSeparatorType(separatorGroup) {
-		return "-"
-	}
-	
-	// 3. 其他情况替换为空格
-	return " "
-}
+	safeLogger.AppendLog(fmt.Sprintf("分割为多笔下注: %s", segmentTexts))
 
-// isSameSeparatorType 检查分隔符组是否为同一类型
-func (p *IntelligentBetParser) isSameSeparatorType(separatorGroup []rune) bool {
-	if len(separatorGroup) == 0 {
-		return false
-	}
-	
-	firstSep := separatorGroup[0]
-	for _, sep := range separatorGroup {
-		if sep != firstSep {
-			return false
-		}
-	}
-	return true
-}
-
-// replaceKeywords 替换关键词（生肖、颜色、尾数）
-func (p *IntelligentBetParser) replaceKeywords(text string) string {
-	// 合并所有关键词映射
-	allKeywords := make(map[string][]int)
-	for keyword, numbers := range p.config.ZodiacMap {
-		allKeywords[keyword] = numbers
-	}
-	for keyword, numbers := range p.config.ColorMap {
-		allKeywords[keyword] = numbers
-	}
-	for keyword, numbers := range p.config.TailMap {
-		allKeywords[keyword] = numbers
-	}
-
-	// 按关键词长度降序排序（从最多字开始替换）
-	keywords := make([]string, 0, len(allKeywords))
-	for keyword := range allKeywords {
-		keywords = append(keywords, keyword)
-	}
+	// 4. 解析每笔下注
+	parsedBets := make([]SingleBetParsing, 0)
+	context := &BetContext{inheritedLotteries: make([]string, 0), inheritedBetTypes: make([]string, 0)}
 
-	// 排序：按长度降序
-	for i := 0; i < len(keywords)-1; i++ {
-		for j := i + 1; j < len(keywords); j++ {
-			if len(keywords[i]) < len(keywords[j]) {
-				keywords[i], keywords[j] = keywords[j], keywords[i]
-			}
-		}
-	}
+	roundTotal := decimal.NewFromInt(0)
 
-	// 替换关键词,且默认不加
-	for _, keyword := range keywords {
-		if numbers, exists := allKeywords[keyword]; exists {
-			numbersStr := make([]string, len(numbers))
-			for i, num := range numbers {
-				numbersStr[i] = fmt.Sprintf("%02d", num)
+	for i, segment := range segments {
+		betID := fmt.Sprintf("%s_bet_%d", roundID, i+1)
+		betTokens, declaration := p.extractDeclarations(segment.Tokens)
+
+		// 存在多种理解方式时，按操作员的选择或置信度最高的理解解析；按原文无法解析时只列出可行理解，不替操作员选择
+		ambiguity := p.detectAmbiguity(betTokens, context)
+		if ambiguity != nil {
+			ambiguity.BetID = betID
+			ambiguity.BetIndex = i + 1
+			if choice, ok := choices[i+1]; ok && choice >= 0 && choice < len(ambiguity.Interpretations) {
+				ambiguity.Selected = choice
 			}
-			replacement := strings.Join(numbersStr, "-")
-
-			var newText strings.Builder
-			lastIndex := 0
-
-			for {
-				index := strings.Index(text[lastIndex:], keyword)
-				if index == -1 {
-					break
+			// 选择的理解与原文相同时沿用原词法单元，保留号码集合表达式
+			if ambiguity.Selected >= 0 {
+				if selected := ambiguity.Interpretations[ambiguity.Selected].Text; selected != renderTokens(betTokens) {
+					betTokens = p.tokenize(selected)
 				}
-
-				realIndex := lastIndex + index
-
-				newText.WriteString(text[lastIndex:realIndex])
-
-				if realIndex > 0 && unicode.IsDigit(rune(text[realIndex-1])) {
-					newText.WriteString("-" + replacement)
-				} else {
-					newText.WriteString(replacement)
-				}
-
-				lastIndex = realIndex + len(keyword)
 			}
-
-			newText.WriteString(text[lastIndex:])
-			text = newText.String()
-		}
-	}
-
-	return text
-}
-
-// removeChineseChars 移除非关键字的所有中文字符
-func (p *IntelligentBetParser) removeChineseChars(text string) string {
-
-	// 清理结束关键词后的空格和符号
-	text = p.cleanEndKeywords(text)
-
-	// 保留的关键词
-	preservedKeywords := make([]string, 0)
-
-	// 添加下注类型关键词
-	for _, aliases := range p.config.BetTypeAliases {
-		preservedKeywords = append(preservedKeywords, aliases...)
-	}
-
-	// 添加体彩关键词
-	for _, aliases := range p.config.LotteryAliases {
-		preservedKeywords = append(preservedKeywords, aliases...)
-	}
-	//  添加结束关键词
-	for _, aliases := range p.config.EndKeywords {
-		preservedKeywords = append(preservedKeywords, aliases...)
-	}
-	//  添加关键字关键词
-	for _, aliases := range p.config.KeywordAliases {
-		preservedKeywords = append(preservedKeywords, aliases...)
-	}
-
-	// 按长度降序排序
-	for i := 0; i < len(preservedKeywords)-1; i++ {
-		for j := i + 1; j < len(preservedKeywords); j++ {
-			if len(preservedKeywords[i]) < len(preservedKeywords[j]) {
-				preservedKeywords[i], preservedKeywords[j] = preservedKeywords[j], preservedKeywords[i]
+			result.Ambiguities = append(result.Ambiguities, *ambiguity)
+		}
+
+		parsed := p.parseBetTokens(betID, betTokens, context)
+		parsed.OriginalText = segment.Text
+		parsed.Warnings = append(parsed.Warnings, segment.Warnings...)
+		if ambiguity != nil && ambiguity.Selected >= 0 {
+			parsed.Warnings = append(parsed.Warnings, fmt.Sprintf("存在多种理解方式，已按\"%s\"解析，可在纠错窗口中选择其他理解",
+				ambiguity.Interpretations[ambiguity.Selected].Label))
+		} else if ambiguity != nil {
+			labels := make([]string, len(ambiguity.Interpretations))
+			for j, interpretation := range ambiguity.Interpretations {
+				labels[j] = interpretation.Label
 			}
+			parsed.HasError = true
+			parsed.ErrorMessage = append(parsed.ErrorMessage, fmt.Sprintf("按原文无法解析，可能的理解: %s，请在纠错窗口中选择",
+				strings.Join(labels, "；")))
 		}
-	}
+		p.checkIgnoredText(&parsed, segment.Ignored)
 
-	// 先用占位符替换要保留的关键词
-	placeholders := make(map[string]string)
-	for i, keyword := range preservedKeywords {
-		if strings.Contains(text, "-"+keyword+"-") {
-			text = strings.ReplaceAll(text, "-"+keyword+"-", keyword)
-		}
-		if strings.Contains(text, "-"+keyword) {
-			text = strings.ReplaceAll(text, "-"+keyword, keyword)
-		}
-		if strings.Contains(text, keyword+"-") {
-			text = strings.ReplaceAll(text, keyword+"-", keyword)
-		}
-		placeholder := fmt.Sprintf("__PRESERVE_%d__", i)
-		if strings.Contains(text, keyword) {
-			text = strings.ReplaceAll(text, keyword, placeholder)
-			placeholders[placeholder] = keyword
-		}
-	}
-
-	// 移除所有中文字符 - 使用简单方法逐字符检查
-	var result strings.Builder
-	for _, char := range text {
-		// 检查是否为中文字符（简化版本）
-		if char >= 0x4e00 && char <= 0x9fff {
-			// 跳过中文字符
-			continue
-		}
-		result.WriteRune(char)
-	}
-	text = result.String()
-
-	// 恢复保留的关键词
-	for placeholder, keyword := range placeholders {
-		safeLogger.AppendLog(fmt.Sprintf("恢复保留的关键词: %s,%s", placeholder, keyword))
-
-		// 标记是否已找到并替换
-		replaced := false
-
-		// 尝试在 BetTypeAliases 中查找并替换
-		for k, alias := range p.config.BetTypeAliases {
-			if slices.Contains(alias, keyword) {
-				text = strings.ReplaceAll(text, placeholder, k)
-				replaced = true // 找到并替换，退出内层 alias 循环
-				break           // 退出 k 循环
-			}
-		}
-		if replaced {
-			continue // 已经替换，处理下一个 placeholder
-		}
-
-		// 尝试在 LotteryAliases 中查找并替换
-		for k, alias := range p.config.LotteryAliases {
-			if slices.Contains(alias, keyword) {
-				text = strings.ReplaceAll(text, placeholder, k)
-				replaced = true
-				break
-			}
-		}
-		if replaced {
-			continue
-		}
-
-		// 尝试在 KeywordAliases 中查找并替换
-		for k, alias := range p.config.KeywordAliases {
-			if slices.Contains(alias, keyword) {
-				text = strings.ReplaceAll(text, placeholder, k)
-				replaced = true
-				break
-			}
-		}
-		if replaced {
-			continue
+		// 核对玩家声明的组数与合计
+		if !parsed.HasError {
+			roundTotal = roundTotal.Add(parsed.BetStatistics.TotalAmount)
 		}
+		p.checkDeclaration(&parsed, declaration, roundTotal)
+		parseJson, _ := json.Marshal(parsed)
+		safeLogger.AppendLog(fmt.Sprintf("解析每笔下注: %s", string(parseJson)))
+		parsedBets = append(parsedBets, parsed)
 
-		// 尝试在 EndKeywords 中查找并替换
-		for k, alias := range p.config.EndKeywords {
-			if slices.Contains(alias, keyword) {
-				text = strings.ReplaceAll(text, placeholder, k)
-				replaced = true
-				break
+		// 更新体彩及下注类型继承状态
+		if len(parsed.LotteryBets) > 0 {
+			context.inheritedLotteries = sortLotteries(sortedKeys(parsed.LotteryBets))
+			betTypes := make(map[string]bool)
+			for _, lotteryInfo := range parsed.LotteryBets {
+				for betType := range lotteryInfo.BetTypeDetails {
+					betTypes[betType] = true
+				}
 			}
-		}
-		if replaced {
-			continue
-		}
-
-		// 如果在所有配置中都没找到，则保持原关键词
-		text = strings.ReplaceAll(text, placeholder, keyword)
-	}
-
-	return text
-}
-
-// cleanEndKeywords 清理结束关键词后的空格和符号
-func (p *IntelligentBetParser) cleanEndKeywords(text string) string {
-	// 获取所有结束关键词
-	endKeywords := p.config.EndKeywords
-
-	// 过滤出实际的结束关键词（去重）
-	uniqueEndKeywords := make([]string, 0)
-	for k, _ := range endKeywords {
-		uniqueEndKeywords = append(uniqueEndKeywords, k)
-	}
-
-	// 按长度降序排序，确保长关键词优先处理
-	for i := 0; i < len(uniqueEndKeywords)-1; i++ {
-		for j := i + 1; j < len(uniqueEndKeywords); j++ {
-			if len(uniqueEndKeywords[i]) < len(uniqueEndKeywords[j]) {
-				uniqueEndKeywords[i], uniqueEndKeywords[j] = uniqueEndKeywords[j], uniqueEndKeywords[i]
+			context.inheritedBetTypes = make([]string, 0)
+			for _, betType := range betTypeOrder {
+				if betTypes[betType] {
+					context.inheritedBetTypes = append(context.inheritedBetTypes, betType)
+				}
 			}
 		}
 	}
 
-	// 定义一个正则表达式，用于匹配“十”到“万”的中文数字
-	chineseNumRe := `(一|二|三|四|五|六|七|八|九|十|百|千|万)`
-
-	for _, keyword := range uniqueEndKeywords {
-		// 步骤一：移除结束关键词后的空格和标点符号
-		// 匹配：关键词 + 任意空格 + 任意标点符号
-		pattern := regexp.QuoteMeta(keyword) + `[\s\p{P}]*`
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
-		}
-		text = re.ReplaceAllString(text, keyword+"")
-
-		// 步骤二：处理该关键词后面紧跟的中文数字
-		// 匹配：关键词 + 紧跟的中文数字
-		chineseNumPattern := regexp.QuoteMeta(keyword) + chineseNumRe + `+`
-		reNum, err := regexp.Compile(chineseNumPattern)
-		if err != nil {
-			continue
-		}
+	result.ParsedBets = parsedBets
 
-		// 查找所有匹配该模式的字符串
-		matches := reNum.FindAllString(text, -1)
-		for _, match := range matches {
-			// 提取中文数字部分
-			numPart := strings.TrimPrefix(match, keyword)
+	// 5. 生成统计信息
+	result.RoundStatistics = p.generateRoundStatistics(parsedBets)
 
-			// 将中文数字转换为阿拉伯数字
-			if val, ok := chineseToNumber(numPart); ok {
-				// 替换原始字符串中的“关键词+中文数字”为“关键词+阿拉伯数字”
-				text = strings.ReplaceAll(text, match, keyword+strconv.Itoa(val))
-			}
+	// 6. 检查错误及提示
+	for _, bet := range parsedBets {
+		if bet.HasError {
+			result.HasError = true
+			result.ErrorMessages = append(result.ErrorMessages, bet.ErrorMessage...)
 		}
+		result.WarningMessages = append(result.WarningMessages, bet.Warnings...)
 	}
 
-	return text
 }
 
 // chineseToNumber 辅助函数：将中文数字（十到万）转换为阿拉伯数字
@@ -505,173 +287,225 @@ func chineseToNumber(chinese string) (int, bool) {
 	return 0, false // 无法转换
 }
 
-// segmentBets 通过金额分割为多笔下注
-func (p *IntelligentBetParser) segmentBets(text string) []string {
-	// 金额模式（按优先级排序）
-	patterns := []string{
-		`各(\d+)`,  // 各20
-		`每组(\d+)`, // 每组20
-	}
+// segmentBets 按金额将词法单元切分为多笔下注，每笔以"各5"、"每组20"等金额结尾
+func (p *IntelligentBetParser) segmentBets(tokens []Token) [][]Token {
+	segments := make([][]Token, 0)
 
-	segments := make([]string, 0)
+	start := 0
+	for i, token := range tokens {
+		if token.Type != TokenAmount {
+			continue
+		}
+		if segment := trimSeparators(tokens[start : i+1]); len(segment) > 0 {
+			segments = append(segments, segment)
+		}
+		start = i + 1
+	}
 
-	// 编译所有正则表达式
-	compiledPatterns := make([]*regexp.Regexp, len(patterns))
-	for i, pattern := range patterns {
-		compiledPatterns[i] = regexp.MustCompile(pattern)
+	// 处理最后一段（如果有剩余内容）
+	if segment := trimSeparators(tokens[start:]); len(segment) > 0 {
+		segments = append(segments, segment)
 	}
 
-	// 查找所有金额位置
-	amountPositions := make([]AmountMatch, 0)
+	return segments
+}
 
-	for _, re := range compiledPatterns {
-		matches := re.FindAllStringSubmatchIndex(text, -1)
-		for _, match := range matches {
-			if len(match) >= 4 { // 确保有捕获组
-				amountPositions = append(amountPositions, AmountMatch{
-					Start: match[0],
-					End:   match[1],
-				})
-			}
-		}
+// trimSeparators 移除首尾的分隔符
+func trimSeparators(tokens []Token) []Token {
+	start, end := 0, len(tokens)
+	for start < end && tokens[start].Type == TokenSeparator {
+		start++
 	}
-
-	// 如果没有找到金额，返回整个文本作为一段
-	if len(amountPositions) == 0 {
-		return []string{strings.TrimSpace(text)}
+	for end > start && tokens[end-1].Type == TokenSeparator {
+		end--
 	}
+	return tokens[start:end]
+}
 
-	// 按位置排序
-	sort.Slice(amountPositions, func(i, j int) bool {
-		return amountPositions[i].Start < amountPositions[j].Start
-	})
+// concatTokens 拼接多个词法单元切片，总是分配新的切片，避免覆盖原切片后面的内容
+func concatTokens(parts ...[]Token) []Token {
+	total := 0
+	for _, part := range parts {
+		total += len(part)
+	}
+	result := make([]Token, 0, total)
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
 
-	// 去重相同位置的匹配
-	uniquePositions := make([]AmountMatch, 0)
-	for i, pos := range amountPositions {
-		if i == 0 || pos.Start != amountPositions[i-1].Start {
-			uniquePositions = append(uniquePositions, pos)
+// spaceToken 拼接片段时插入的空白分隔符
+var spaceToken = Token{Type: TokenSeparator, Text: " "}
+
+// backfillSegmentAmounts 金额回填：未标明金额的下注片段沿用后续片段的金额
+// 例如"三中三2-3-4拖5-6-7 复式三中三(23-19-37-41)每组各5"中拖码部分没有金额，沿用后面的"各5"
+func (p *IntelligentBetParser) backfillSegmentAmounts(segments [][]Token) []BetSegment {
+	// 1. 将每段拆分为下注片段
+	fragments := make([][]Token, 0, len(segments))
+	for _, segment := range segments {
+		fragments = append(fragments, p.splitBetFragments(segment)...)
+	}
+
+	// 2. 片段开头的声明及无法识别的文字（如金额后紧跟的"合计540"、"元"）归入上一笔下注
+	merged := make([][]Token, 0, len(fragments))
+	for _, fragment := range fragments {
+		leading := 0
+		for leading < len(fragment) && isLeadingAttachment(fragment[leading]) {
+			leading++
+		}
+		if leading > 0 && len(merged) > 0 {
+			merged[len(merged)-1] = concatTokens(merged[len(merged)-1], []Token{spaceToken}, trimSeparators(fragment[:leading]))
+			fragment = trimSeparators(fragment[leading:])
+			if len(fragment) == 0 {
+				continue
+			}
 		}
+		merged = append(merged, fragment)
 	}
+	fragments = merged
 
-	// 根据金额位置分割文本
-	lastEnd := 0
-	for i, pos := range uniquePositions {
-		// 分割点：当前金额表达式的结束位置
-		splitPoint := pos.End
+	// 3. 没有金额的片段沿用后续第一个带金额片段的金额
+	result := make([]BetSegment, 0, len(fragments))
+	for i, fragment := range fragments {
+		betSegment := BetSegment{
+			Tokens:   fragment,
+			Warnings: make([]string, 0),
+			Ignored:  collectIgnoredText(fragment),
+		}
 
-		// 如果不是第一个金额，从上一个分割点开始
-		if i > 0 {
-			segmentText := text[lastEnd:splitPoint]
-			segmentText = p.cleanSegment(segmentText)
-			if segmentText != "" {
-				segments = append(segments, segmentText)
-			}
-		} else {
-			// 第一个金额，从文本开头到金额结束
-			segmentText := text[0:splitPoint]
-			segmentText = p.cleanSegment(segmentText)
-			if segmentText != "" {
-				segments = append(segments, segmentText)
+		if findStake(fragment) == nil {
+			for _, next := range fragments[i+1:] {
+				if stake := findStake(next); stake != nil {
+					betSegment.Tokens = concatTokens(fragment, []Token{spaceToken}, stake)
+					betSegment.Warnings = append(betSegment.Warnings,
+						fmt.Sprintf("下注\"%s\"未标明金额，已沿用后续金额\"%s\"，请确认", renderTokens(fragment), renderTokens(stake)))
+					break
+				}
 			}
 		}
 
-		lastEnd = splitPoint
+		betSegment.Text = renderTokens(betSegment.Tokens)
+		result = append(result, betSegment)
 	}
 
-	// 处理最后一段（如果有剩余文本）
-	if lastEnd < len(text) {
-		segmentText := text[lastEnd:]
-		segmentText = p.cleanSegment(segmentText)
-		if segmentText != "" {
-			segments = append(segments, segmentText)
-		}
-	}
+	return result
+}
 
-	return segments
+// isLeadingAttachment 位于片段开头、应归属上一笔下注的词法单元：声明及无法识别的文字
+func isLeadingAttachment(token Token) bool {
+	switch token.Type {
+	case TokenSeparator, TokenDeclaration, TokenUnknown:
+		return true
+	}
+	return false
 }
 
-// cleanSegment 清理分段文本，移除前后的"-"符号
-func (p *IntelligentBetParser) cleanSegment(segment string) string {
-	// 去除首尾空白
-	segment = strings.TrimSpace(segment)
+// splitBetFragments 将一段按下注类型拆分为多个下注片段
+// 只有当前片段已包含下注类型和号码时，后面出现的下注类型才视为新片段的开始；
+// 不含号码的片段（如单独的"三中三每组各5"）会被合并回相邻片段
+func (p *IntelligentBetParser) splitBetFragments(segment []Token) [][]Token {
+	// 按下注类型位置切分
+	rawFragments := make([][]Token, 0)
+	fragmentStart := 0
+	for i, token := range segment {
+		if token.Type != TokenBetType {
+			continue
+		}
+		current := segment[fragmentStart:i]
+		if !hasBetType(current) || !hasBetNumbers(current) {
+			continue
+		}
+
+		// 向前吸收紧邻的体彩、复式关键词
+		boundary := i
+		for j := i - 1; j >= fragmentStart; j-- {
+			if segment[j].Type == TokenSeparator {
+				continue
+			}
+			if segment[j].Type == TokenLottery || (segment[j].Type == TokenMode && segment[j].Value == "复式") {
+				boundary = j
+				continue
+			}
+			break
+		}
+		if !hasBetNumbers(segment[fragmentStart:boundary]) {
+			continue
+		}
 
-	// 移除开头的"-"符号
-	for strings.HasPrefix(segment, "-") {
-		segment = strings.TrimPrefix(segment, "-")
-		segment = strings.TrimSpace(segment)
+		rawFragments = append(rawFragments, trimSeparators(segment[fragmentStart:boundary]))
+		fragmentStart = boundary
 	}
+	rawFragments = append(rawFragments, trimSeparators(segment[fragmentStart:]))
 
-	// 移除结尾的"-"符号
-	for strings.HasSuffix(segment, "-") {
-		segment = strings.TrimSuffix(segment, "-")
-		segment = strings.TrimSpace(segment)
+	// 合并不含号码的片段：带金额的视为上一片段的结尾，不带金额的视为下一片段的开头
+	fragments := make([][]Token, 0, len(rawFragments))
+	var pending []Token
+	for _, fragment := range rawFragments {
+		if pending != nil {
+			fragment = concatTokens(pending, []Token{spaceToken}, fragment)
+			pending = nil
+		}
+		if hasBetNumbers(fragment) {
+			fragments = append(fragments, fragment)
+			continue
+		}
+		if findStake(fragment) != nil && len(fragments) > 0 {
+			fragments[len(fragments)-1] = concatTokens(fragments[len(fragments)-1], []Token{spaceToken}, fragment)
+			continue
+		}
+		pending = fragment
+	}
+	if pending != nil {
+		if len(fragments) > 0 {
+			fragments[len(fragments)-1] = concatTokens(fragments[len(fragments)-1], []Token{spaceToken}, pending)
+		} else {
+			fragments = append(fragments, pending)
+		}
 	}
 
-	return segment
+	return fragments
 }
 
-// identifyLotteries 识别体彩类型（从最多字开始替换）
-func (p *IntelligentBetParser) identifyLotteries(text string) []string {
-	result := make([]string, 0)
-
-	// 按别名长度降序排序
-	for lotteryType, aliases := range p.config.LotteryAliases {
-		// 对当前体彩的别名按长度排序
-		sortedAliases := make([]string, len(aliases))
-		copy(sortedAliases, aliases)
-
-		for i := 0; i < len(sortedAliases)-1; i++ {
-			for j := i + 1; j < len(sortedAliases); j++ {
-				if len(sortedAliases[i]) < len(sortedAliases[j]) {
-					sortedAliases[i], sortedAliases[j] = sortedAliases[j], sortedAliases[i]
-				}
-			}
+// findStake 查找金额关键词及金额（如"各5"、"每组20"），不存在时返回nil
+func findStake(tokens []Token) []Token {
+	for i, token := range tokens {
+		if token.Type != TokenAmount {
+			continue
 		}
-
-		// 从最长别名开始匹配
-		for _, alias := range sortedAliases {
-			if strings.Contains(text, alias) {
-				text = strings.ReplaceAll(text, alias, "")
-				result = append(result, lotteryType)
-				break
+		for j := i - 1; j >= 0; j-- {
+			if tokens[j].Type == TokenEndKeyword {
+				return []Token{tokens[j], token}
 			}
 		}
 	}
-	return result
+	return nil
 }
 
-// identifyBetTypeFlags 识别下注类型标识（新版本）
-func (p *IntelligentBetParser) identifyBetTypeFlags(text string) BetTypeFlags {
-	flags := BetTypeFlags{}
-
-	// 检查三中三关键词
-	if strings.Contains(text, "三中三") {
-		flags.HasThreeOfThree = true
-	}
-
-	// 检查三中二关键词
-	if strings.Contains(text, "三中二") {
-		flags.HasThreeOfTwo = true
-	}
-
-	// 检查二中二关键词
-	if strings.Contains(text, "二中二") {
-		flags.HasTwoOfTwo = true
+// hasBetType 检查是否包含下注类型
+func hasBetType(tokens []Token) bool {
+	for _, token := range tokens {
+		if token.Type == TokenBetType {
+			return true
+		}
 	}
+	return false
+}
 
-	// 检查特碰关键词
-	if strings.Contains(text, "特碰") {
-		flags.HasSpecial = true
+// hasBetNumbers 检查是否包含号码（金额、声明中的数字不是号码）
+func hasBetNumbers(tokens []Token) bool {
+	for _, token := range tokens {
+		if token.isNumeric() {
+			return true
+		}
 	}
-
-	return flags
+	return false
 }
 
 // processBetType 处理单个下注类型，返回该类型的所有模式信息
 func (p *IntelligentBetParser) processBetType(
 	betType string,
-	text string,
+	syntax betSyntax,
 ) (*BetTypeDetail, error) {
 
 	detail := &BetTypeDetail{
@@ -681,8 +515,8 @@ func (p *IntelligentBetParser) processBetType(
 		TotalAmount: decimal.NewFromInt(0),
 	}
 	// 检查并处理拖码模式
-	if p.isDragBet(text) {
-		modeInfo, err := p.processDragMode(betType, text)
+	if syntax.HasDrag {
+		modeInfo, err := p.processDragMode(betType, syntax)
 		if err != nil {
 			return nil, err
 		}
@@ -692,8 +526,8 @@ func (p *IntelligentBetParser) processBetType(
 	}
 
 	// 检查并处理复式模式
-	if p.isComplexBet(betType, text) {
-		modeInfo, err := p.processComplexMode(betType, text)
+	if p.isComplexBet(betType, syntax) {
+		modeInfo, err := p.processComplexMode(betType, syntax)
 		if err != nil {
 			return nil, err
 		}
@@ -702,15 +536,65 @@ func (p *IntelligentBetParser) processBetType(
 		detail.TotalAmount = detail.TotalAmount.Add(modeInfo.Amount)
 	}
 
+	// 检查并处理多组模式（每组号码个数正好等于玩法所需个数）
+	if p.isMultipleBet(betType, syntax) {
+		modeInfo, err := p.processMultipleMode(betType, syntax)
+		if err != nil {
+			return nil, err
+		}
+		detail.Modes["multiple"] = *modeInfo
+		detail.TotalGroups += modeInfo.Groups
+		detail.TotalAmount = detail.TotalAmount.Add(modeInfo.Amount)
+	}
+
+	// 写了下注类型但没有组成任何下注组合，不能当作0组0元的下注
+	if detail.TotalGroups == 0 {
+		return nil, fmt.Errorf("%s没有有效的下注号码，%s每组需要%d个号码", betType, betType, betTypeNumberCount(betType))
+	}
+
 	return detail, nil
 }
 
+// processMultipleMode 处理多组模式，如"30-34-45 14-19-23三中三各30"为两组三中三
+func (p *IntelligentBetParser) processMultipleMode(
+	betType string,
+	syntax betSyntax,
+) (*BetModeInfo, error) {
+	unitAmount := syntax.Amount
+	if unitAmount.IsZero() {
+		return nil, errors.New("存在多组下注，但不存在下注金额,请在下注金额前手动添加'各'或'每组'表示每组金额")
+	}
+
+	combinations, err := p.generateNCombinations(syntax.Groups, betTypeNumberCount(betType))
+	if err != nil {
+		return nil, err
+	}
+
+	modeInfo := &BetModeInfo{
+		ModeName:   "multiple",
+		BetDetails: make([]BetDetail, 0, len(combinations)),
+		UnitAmount: unitAmount,
+	}
+	for _, combo := range combinations {
+		modeInfo.BetDetails = append(modeInfo.BetDetails, BetDetail{
+			Numbers:     combo.Numbers,
+			Amount:      unitAmount,
+			Description: fmt.Sprintf("%s: %s", betType, combo.OriginalText),
+		})
+	}
+
+	modeInfo.Groups = len(combinations)
+	modeInfo.Amount = unitAmount.Mul(decimal.NewFromInt(int64(len(combinations))))
+
+	return modeInfo, nil
+}
+
 // processComplexMode 处理复式
 func (p *IntelligentBetParser) processComplexMode(
 	betType string,
-	text string,
+	syntax betSyntax,
 ) (*BetModeInfo, error) {
-	unitAmount := p.extractAmountSmart(text)
+	unitAmount := syntax.Amount
 	if unitAmount.IsZero() {
 		return nil, errors.New("存在复式下注，但不存在下注金额,请在下注金额前手动添加'各'或'每组'表示每组金额")
 	}
@@ -722,23 +606,24 @@ func (p *IntelligentBetParser) processComplexMode(
 	}
 
 	var combinations []BetCombination
+	var err error
 
 	switch betType {
 	case "三中三":
-		combinations, _ = p.generateNCombinations(text, 3)
+		combinations, err = p.generateNCombinations(syntax.Groups, 3)
 	case "二中二":
-		combinations, _ = p.generateNCombinations(text, 2)
+		combinations, err = p.generateNCombinations(syntax.Groups, 2)
 	case "三中二":
-		combinations, _ = p.generateNCombinations(text, 3)
+		combinations, err = p.generateNCombinations(syntax.Groups, 3)
 	case "特碰":
-		combinations, _ = p.generateNCombinations(text, 2)
+		combinations, err = p.generateNCombinations(syntax.Groups, 2)
 	default:
 		return nil, fmt.Errorf("不支持的下注类型: %s", betType)
 	}
 
-	// 如果没有找到组合，返回错误
-	if combinations == nil {
-		return nil, errors.New("未找到有效的下注组合")
+	// 号码越界或没有找到组合，返回错误
+	if err != nil {
+		return nil, err
 	}
 
 	for _, combo := range combinations {
@@ -758,9 +643,9 @@ func (p *IntelligentBetParser) processComplexMode(
 // processDragMode 处理拖码模式，支持多组输入
 func (p *IntelligentBetParser) processDragMode(
 	betType string,
-	text string,
+	syntax betSyntax,
 ) (*BetModeInfo, error) {
-	unitAmount := p.extractAmountSmart(text)
+	unitAmount := syntax.Amount
 	if unitAmount.IsZero() {
 		return nil, errors.New("存在拖类型下注，但不存在下注金额,请在下注金额前手动添加'各'或'每组'表示每组金额")
 	}
@@ -771,42 +656,30 @@ func (p *IntelligentBetParser) processDragMode(
 		UnitAmount: unitAmount,
 	}
 
-	// 使用正则表达式按空格或逗号分割整个文本，以处理多组拖码
-	reDragGroups := regexp.MustCompile(`(\d{1,2}(?:-\d{1,2})*拖\d{1,2}(?:-\d{1,2})*(?:拖\d{1,2}(?:-\d{1,2})*)?)`)
-	dragStrings := reDragGroups.FindAllString(text, -1)
-
-	if len(dragStrings) == 0 {
+	if len(syntax.Drags) == 0 {
 		return nil, errors.New("未找到有效的拖码组合")
 	}
 
 	var totalCombinations int64 = 0
 
-	for _, dragString := range dragStrings {
+	for _, drag := range syntax.Drags {
 		// 解析单个拖码组，如 "1-2-3拖10-11-12"
-		dragGroups, err := p.parseDragGroups(dragString)
+		dragGroups, err := p.parseDragGroups(drag)
 		if err != nil {
 			return nil, err
 		}
 
-		// 根据下注类型确定需要的数字个数
-		var requiredCount int
-		switch betType {
-		case "三中三", "三中二":
-			requiredCount = 3
-		case "二中二", "特碰":
-			requiredCount = 2
-		default:
-			requiredCount = 2
-		}
-
 		// 生成拖码组合（笛卡尔积）
-		combinations, _ := p.generateCartesianProduct(dragGroups, requiredCount)
+		combinations, err := p.generateCartesianProduct(dragGroups, betTypeNumberCount(betType))
+		if err != nil {
+			return nil, fmt.Errorf("%s拖码%s: %v", betType, drag.describe(), err)
+		}
 
 		for _, combo := range combinations {
 			modeInfo.BetDetails = append(modeInfo.BetDetails, BetDetail{
 				Numbers:     combo,
 				Amount:      unitAmount,
-				Description: fmt.Sprintf("%s拖码: %s", betType, dragString),
+				Description: fmt.Sprintf("%s拖码: %s", betType, drag.describe()),
 			})
 		}
 		totalCombinations += int64(len(combinations))
@@ -818,77 +691,26 @@ func (p *IntelligentBetParser) processDragMode(
 	return modeInfo, nil
 }
 
-// generateNCombinations 完整优化版本
-func (p *IntelligentBetParser) generateNCombinations(text string, n int) ([]BetCombination, error) {
-	// 使用最简单的正则，完全兼容Go
-	re := regexp.MustCompile(`\d{1,2}(?:-\d{1,2})*`)
-
-	// 找到所有匹配及其位置
-	matchIndices := re.FindAllStringIndex(text, -1)
-
+// generateNCombinations 从号码组生成n个号码的组合：个数正好为n的号码组为一组，多于n个的生成所有组合
+func (p *IntelligentBetParser) generateNCombinations(groups []numberGroup, n int) ([]BetCombination, error) {
 	var allCombinations []BetCombination
-	for _, indices := range matchIndices {
-		start, end := indices[0], indices[1]
-		match := text[start:end]
-
-		// 检查前后字符，过滤掉"拖"字相邻或数字相邻的情况
-		shouldSkip := false
-
-		// 检查前一个字符
-		if start > 0 {
-			// 安全地获取前一个字符（处理UTF-8）
-			beforeText := text[:start]
-			if len(beforeText) > 0 {
-				runes := []rune(beforeText)
-				if len(runes) > 0 {
-					prevRune := runes[len(runes)-1]
-					if prevRune == '拖' || unicode.IsDigit(prevRune) {
-						shouldSkip = true
-					}
-				}
-			}
-		}
-
-		// 检查后一个字符
-		if end < len(text) && !shouldSkip {
-			// 安全地获取后一个字符（处理UTF-8）
-			afterText := text[end:]
-			if len(afterText) > 0 {
-				runes := []rune(afterText)
-				if len(runes) > 0 {
-					nextRune := runes[0]
-					if nextRune == '拖' || unicode.IsDigit(nextRune) {
-						shouldSkip = true
-					}
-				}
-			}
-		}
-
-		if shouldSkip {
+	for _, group := range groups {
+		if group.Count < n {
 			continue
 		}
 
-		// 分割字符串得到单个数字
-		numStrings := strings.Split(match, "-")
-		if len(numStrings) < n {
-			continue
-		}
-
-		// 将字符串转换为整数
-		var numbers []int
-		for _, s := range numStrings {
-			num, err := strconv.Atoi(s)
-			if err != nil {
-				continue
-			}
-			numbers = append(numbers, num)
+		// 将字符串转换为整数，越界号码直接报错，重复号码去重（提示信息由validateBetNumbers生成）
+		numbers, err := parseNumberGroup(group.Text)
+		if err != nil {
+			return nil, err
 		}
+		numbers, _ = dedupeNumbers(numbers)
 
 		// 确保组合的数字个数正好是n
 		if len(numbers) == n {
 			allCombinations = append(allCombinations, BetCombination{
 				Numbers:      numbers,
-				OriginalText: match,
+				OriginalText: group.describe(),
 			})
 		} else if len(numbers) > n {
 			// 如果组合数字多于n个，则生成所有n个数字的组合
@@ -899,7 +721,7 @@ func (p *IntelligentBetParser) generateNCombinations(text string, n int) ([]BetC
 			for _, combo := range combinations {
 				allCombinations = append(allCombinations, BetCombination{
 					Numbers:      combo,
-					OriginalText: match,
+					OriginalText: group.describe(),
 				})
 			}
 		}
@@ -939,24 +761,16 @@ func (p *IntelligentBetParser) getCombinations(numbers []int, n int) ([][]int, e
 }
 
 // parseDragGroups 解析单个拖码组，如"1-2-3拖10-11-12"
-func (p *IntelligentBetParser) parseDragGroups(text string) ([][]int, error) {
+func (p *IntelligentBetParser) parseDragGroups(drag dragExpr) ([][]int, error) {
 	var groups [][]int
 
-	// 使用正则表达式按 "拖" 分割字符串，并提取数字
-	re := regexp.MustCompile(`\b\d{1,2}(?:-\d{1,2})*\b`)
-	parts := strings.Split(text, "拖")
-
-	for _, part := range parts {
-		matches := re.FindAllString(part, -1)
-		var groupNumbers []int
-		for _, match := range matches {
-			numStrings := strings.Split(match, "-")
-			for _, s := range numStrings {
-				if num, err := strconv.Atoi(s); err == nil {
-					groupNumbers = append(groupNumbers, num)
-				}
-			}
+	for _, part := range drag.Parts {
+		groupNumbers, err := parseNumberGroup(part.Text)
+		if err != nil {
+			return nil, err
 		}
+		// 同一拖码组内的重复号码去重
+		groupNumbers, _ = dedupeNumbers(groupNumbers)
 		if len(groupNumbers) > 0 {
 			groups = append(groups, groupNumbers)
 		}
@@ -969,11 +783,11 @@ func (p *IntelligentBetParser) parseDragGroups(text string) ([][]int, error) {
 	return groups, nil
 }
 
-// generateCartesianProduct 生成笛卡尔积
+// generateCartesianProduct 生成笛卡尔积，每组号码各取一个，组数须等于每组所需的号码个数
 func (p *IntelligentBetParser) generateCartesianProduct(sets [][]int, requiredSize int) ([][]int, error) {
 	var result [][]int
-	if len(sets) == 0 {
-		return result, nil
+	if len(sets) != requiredSize {
+		return nil, fmt.Errorf("拖码分为%d段，每组需要%d个号码，段数须与号码个数相同", len(sets), requiredSize)
 	}
 
 	// 递归生成笛卡尔积
@@ -1005,20 +819,31 @@ func (p *IntelligentBetParser) generateCartesianProduct(sets [][]int, requiredSi
 	}
 
 	generate(0, []int{})
+	if len(result) == 0 {
+		return nil, errors.New("未找到有效的拖码组合")
+	}
 	return result, nil
 }
 
-// parseSingleBet 解析单笔下注（新优化版本）
+// parseSingleBet 解析单笔下注文本
 func (p *IntelligentBetParser) parseSingleBet(betID string, segment string, context *BetContext) SingleBetParsing {
+	return p.parseBetTokens(betID, p.tokenize(segment), context)
+}
+
+// parseBetTokens 解析单笔下注的词法单元
+func (p *IntelligentBetParser) parseBetTokens(betID string, tokens []Token, context *BetContext) SingleBetParsing {
 	result := SingleBetParsing{
 		BetID:        betID,
-		OriginalText: segment,
+		OriginalText: renderTokens(tokens),
 		LotteryBets:  make(map[string]LotteryBetInfo),
 		ErrorMessage: make([]string, 0),
+		Warnings:     make([]string, 0),
 	}
 
-	// 1. 识别体彩类型,并移除相关字符串
-	lotteries := p.identifyLotteries(segment)
+	syntax := p.analyzeBetTokens(tokens)
+
+	// 1. 识别体彩类型
+	lotteries := syntax.Lotteries
 	if len(lotteries) == 0 && len(context.inheritedLotteries) > 0 {
 		lotteries = context.inheritedLotteries
 	}
@@ -1026,80 +851,49 @@ func (p *IntelligentBetParser) parseSingleBet(betID string, segment string, cont
 		lotteries = []string{"新澳"}
 	}
 
-	// 2. 识别下注类型标识
-	betTypeFlags := p.identifyBetTypeFlags(segment)
+	// 2. 校验号码范围及重复号码
+	numberWarnings, err := p.validateBetNumbers(syntax)
+	if err != nil {
+		result.HasError = true
+		result.ErrorMessage = append(result.ErrorMessage, err.Error())
+		return result
+	}
+	result.Warnings = append(result.Warnings, numberWarnings...)
 
-	// 3. 为每个体彩处理
+	// 3. 识别下注类型，没写下注类型但有号码时沿用上一笔的下注类型，如"三中三 30-34-45一组30 14-19-23一组30"
+	if len(syntax.BetTypes) == 0 && len(syntax.Groups)+len(syntax.Drags) > 0 && len(context.inheritedBetTypes) > 0 {
+		for _, betType := range context.inheritedBetTypes {
+			syntax.BetTypes[betType] = true
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("未写下注类型，沿用上一笔的下注类型: %s", strings.Join(context.inheritedBetTypes, "、")))
+	}
+	betTypes := syntax.orderedBetTypes()
+	if len(betTypes) == 0 {
+		result.HasError = true
+		result.ErrorMessage = append(result.ErrorMessage, "没有识别到任何的下注类型,请检查是否有下注包含：三中三、二中二、三中二、特碰，一种或多种下注类型")
+		return result
+	}
+
+	// 4. 为每个体彩处理每种存在的下注类型
 	for _, lottery := range lotteries {
 		lotteryInfo := LotteryBetInfo{
 			LotteryType:    lottery,
-			BetTypeFlags:   betTypeFlags,
+			BetTypeFlags:   syntax.betTypeFlags(),
 			BetTypeDetails: make(map[string]BetTypeDetail),
 			TotalAmount:    decimal.NewFromInt(0),
 			TotalGroups:    0,
 		}
 
-		// 4. 处理每种存在的下注类型
-		if betTypeFlags.HasThreeOfThree {
-			detail, err := p.processBetType("三中三", segment)
-			if err != nil {
-				result.HasError = true
-				result.ErrorMessage = append(result.ErrorMessage, err.Error())
-				return result
-			} else {
-				lotteryInfo.BetTypeDetails["三中三"] = *detail
-				lotteryInfo.TotalAmount = lotteryInfo.TotalAmount.Add(detail.TotalAmount)
-				lotteryInfo.TotalGroups += detail.TotalGroups
-			}
-		}
-
-		if betTypeFlags.HasTwoOfTwo {
-			detail, err := p.processBetType("二中二", segment)
-			if err != nil {
-				result.HasError = true
-				result.ErrorMessage = append(result.ErrorMessage, err.Error())
-				return result
-			} else {
-				lotteryInfo.BetTypeDetails["二中二"] = *detail
-				lotteryInfo.TotalAmount = lotteryInfo.TotalAmount.Add(detail.TotalAmount)
-				lotteryInfo.TotalGroups += detail.TotalGroups
-			}
-		}
-
-		if betTypeFlags.HasThreeOfTwo {
-			detail, err := p.processBetType("三中二", segment)
-			if err != nil {
-				result.HasError = true
-				result.ErrorMessage = append(result.ErrorMessage, err.Error())
-				return result
-			} else {
-				lotteryInfo.BetTypeDetails["三中二"] = *detail
-				lotteryInfo.TotalAmount = lotteryInfo.TotalAmount.Add(detail.TotalAmount)
-				lotteryInfo.TotalGroups += detail.TotalGroups
-			}
-		}
-
-		if betTypeFlags.HasSpecial {
-			detail, err := p.processBetType("特碰", segment)
+		for _, betType := range betTypes {
+			detail, err := p.processBetType(betType, syntax)
 			if err != nil {
 				result.HasError = true
 				result.ErrorMessage = append(result.ErrorMessage, err.Error())
 				return result
-			} else {
-				lotteryInfo.BetTypeDetails["特碰"] = *detail
-				lotteryInfo.TotalAmount = lotteryInfo.TotalAmount.Add(detail.TotalAmount)
-				lotteryInfo.TotalGroups += detail.TotalGroups
 			}
-		}
-
-		// 检查是否有下注类型但没有具体号码
-		hasAnyBetType := betTypeFlags.HasThreeOfThree || betTypeFlags.HasTwoOfTwo ||
-			betTypeFlags.HasThreeOfTwo || betTypeFlags.HasSpecial
-
-		if !hasAnyBetType {
-			result.HasError = true
-			result.ErrorMessage = append(result.ErrorMessage, "没有识别到任何的下注类型,请检查是否有下注包含：三中三、二中二、三中二、特碰，一种或多种下注类型")
-			return result
+			lotteryInfo.BetTypeDetails[betType] = *detail
+			lotteryInfo.TotalAmount = lotteryInfo.TotalAmount.Add(detail.TotalAmount)
+			lotteryInfo.TotalGroups += detail.TotalGroups
 		}
 
 		result.LotteryBets[lottery] = lotteryInfo
@@ -1142,108 +936,24 @@ func (p *IntelligentBetParser) generateNewBetStatistics(lotteryBets map[string]L
 	return stats
 }
 
-// extractAmountSmart 智能提取金额
-func (p *IntelligentBetParser) extractAmountSmart(text string) decimal.Decimal {
-	// 金额模式（按优先级排序）
-	patterns := []string{
-		`各(\d+)`,  // 各20
-		`每组(\d+)`, // 每组20
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(text); len(matches) > 1 {
-			if amount, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				return decimal.NewFromFloat(amount)
-			}
-		}
-	}
-	return decimal.NewFromInt(0)
+// isComplexBet 检查是否为复式下注：存在号码个数多于玩法所需个数的号码组
+func (p *IntelligentBetParser) isComplexBet(betType string, syntax betSyntax) bool {
+	return syntax.hasGroupOfSize(betTypeNumberCount(betType) + 1)
 }
 
-// isComplexBet 检查是否为复式下注
-func (p *IntelligentBetParser) isComplexBet(betType string, text string) bool {
-	// 规则1: 文本中包含"复式"关键词
-	// if strings.Contains(text, "复式") {
-	// 	return true
-	// }
+// isMultipleBet 检查是否为多组下注：不是复式，且存在号码个数正好等于玩法所需个数的号码组
+func (p *IntelligentBetParser) isMultipleBet(betType string, syntax betSyntax) bool {
+	return !p.isComplexBet(betType, syntax) && syntax.hasGroupOfSize(betTypeNumberCount(betType))
+}
 
+// betTypeNumberCount 下注类型每组所需的号码个数
+func betTypeNumberCount(betType string) int {
 	switch betType {
 	case "三中三", "三中二":
-		// 三中三或三中二: 连续4个或以上数字
-		return p.hasConsecutiveNumbers(text, 4)
-	case "二中二", "特碰":
-		// 二中二或特碰: 连续3个或以上数字
-		return p.hasConsecutiveNumbers(text, 3)
-	}
-	return false
-}
-
-// hasConsecutiveNumbers 检查文本中是否存在连续的n个数字,需要排除"拖"字
-func (p *IntelligentBetParser) hasConsecutiveNumbers(text string, n int) bool {
-	if n <= 0 {
-		return false
-	}
-
-	// 匹配所有数字组合
-	re := regexp.MustCompile(`\d{1,2}(?:-\d{1,2})*`)
-	matchIndices := re.FindAllStringIndex(text, -1)
-
-	for _, indices := range matchIndices {
-		start, end := indices[0], indices[1]
-		match := text[start:end]
-
-		// 检查前后字符
-		shouldSkip := false
-
-		// 检查前一个字符
-		if start > 0 {
-			beforeText := text[:start]
-			if len(beforeText) > 0 {
-				runes := []rune(beforeText)
-				if len(runes) > 0 {
-					prevRune := runes[len(runes)-1]
-					if prevRune == '拖' || unicode.IsDigit(prevRune) {
-						shouldSkip = true
-					}
-				}
-			}
-		}
-
-		// 检查后一个字符
-		if end < len(text) && !shouldSkip {
-			afterText := text[end:]
-			if len(afterText) > 0 {
-				runes := []rune(afterText)
-				if len(runes) > 0 {
-					nextRune := runes[0]
-					if nextRune == '拖' || unicode.IsDigit(nextRune) {
-						shouldSkip = true
-					}
-				}
-			}
-		}
-
-		if shouldSkip {
-			continue
-		}
-
-		// 验证这个匹配确实包含至少n个数字
-		numStrings := strings.Split(match, "-")
-		if len(numStrings) >= n {
-			return true
-		}
-	}
-
-	return false
-}
-
-// isDragBet 检查是否拖码下注
-func (p *IntelligentBetParser) isDragBet(text string) bool {
-	if strings.Contains(text, "拖") {
-		return true
+		return 3
+	default:
+		return 2
 	}
-	return false
 }
 
 // generateBetStatistics 生成单笔下注统计（保留旧版本兼容）
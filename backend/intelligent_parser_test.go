@@ -0,0 +1,220 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// newTestParser 按默认配置创建解析器，生肖号码按固定日期（2025年，蛇年）生成，结果与运行日期无关
+func newTestParser() *IntelligentBetParser {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 0, at))
+}
+
+func TestParseBetString(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		groups  int    // 整轮有效下注的总组数
+		amount  string // 整轮有效下注的总金额
+	}{
+		// requirement.txt中的下注格式
+		{
+			name:   "格式1: 拖码及复式转二中二，拖码沿用后面的金额",
+			input:  "三中三2.3.4拖5.6.7拖10.11.12 27组\n澳\n复试三中三转二中二\n（23+19+37+41+42+47+13+38）三中三一组各5",
+			groups: 27 + 56 + 28,
+			amount: "555",
+		},
+		{
+			name:   "格式2: 每行一组，沿用首行的下注类型",
+			input:  "三中三\n\n30，34，45一组30\n\n14，19，23一组30\n\n23，35，29一组40",
+			groups: 3,
+			amount: "100",
+		},
+		{
+			name:   "格式3: 三中三三中二复式",
+			input:  "三中三三中二复式10-20-30-40各 25",
+			groups: 4 + 4,
+			amount: "200",
+		},
+		{
+			name:    "格式4: 下注类型写在金额之后暂不支持，须报错",
+			input:   "36-34-24-27-47-21-48各10特碰新澳老澳",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:   "格式5: 同一组号码分别下注复式三中三及二中二",
+			input:  "37.23.35.30.40复式三中三各30\n37.23.35.30.40二中二各20",
+			groups: 10 + 10,
+			amount: "500",
+		},
+		{
+			name:    "格式6: 拖全场暂不支持，须报错而不是0组",
+			input:   "三中三21.35拖全场各20",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:    "格式7: 没写下注类型，须由操作员选择理解",
+			input:   "5–13–32，7–23–26、8–22–23、11–38–43、26–37–42、5–24–34、26–36–42、27–36–42、15–41–48、37–38–42、8–24–47、28–42–49、7–36–41=各10元",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:    "格式8: 以等号标明金额暂不支持，须报错",
+			input:   "3中3\n16-18-23=20\n7-9-13=10",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:   "格式9: 生肖复式",
+			input:  "龙兔复试三中三，三中二各 15",
+			groups: 56 + 56,
+			amount: "1680",
+		},
+		{
+			name:    "格式10: 下注类型后直接写金额暂不支持，须报错",
+			input:   "新.三中三3二中二各3\n36.19.31.30.33.18\n二中二各5\n36-19\n31-30\n33-18\n\n旧.三中三二中二各2\n36.19.31.30.33.18",
+			wantErr: true,
+			groups:  15,
+			amount:  "75",
+		},
+
+		// 没有组成任何下注组合时须报错
+		{
+			name:    "拖码段数少于每组号码个数",
+			input:   "三中三1-2拖3各5",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:    "只有下注类型及金额，没有号码",
+			input:   "三中三1.2.3各5 二中二各5",
+			wantErr: true,
+			groups:  1,
+			amount:  "5",
+		},
+		{
+			name:    "号码个数少于每组所需个数",
+			input:   "三中三1.2各5",
+			wantErr: true,
+			amount:  "0",
+		},
+		{
+			name:   "拖码",
+			input:  "二中二1.2拖3.4各10",
+			groups: 4,
+			amount: "40",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError != tt.wantErr {
+				t.Fatalf("HasError = %v, want %v, errors: %v", result.HasError, tt.wantErr, result.ErrorMessages)
+			}
+			if got := result.RoundStatistics.TotalGroups; got != tt.groups {
+				t.Errorf("TotalGroups = %d, want %d", got, tt.groups)
+			}
+			if got := result.RoundStatistics.TotalAmount; !got.Equal(decimal.RequireFromString(tt.amount)) {
+				t.Errorf("TotalAmount = %s, want %s", got, tt.amount)
+			}
+			for _, bet := range result.ParsedBets {
+				if !bet.HasError && bet.BetStatistics.TotalGroups == 0 {
+					t.Errorf("下注%q没有报错但为0组", bet.OriginalText)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBetStringInterpretationChoice(t *testing.T) {
+	input := "5–13–32，7–23–26、8–22–23、11–38–43、26–37–42、5–24–34、26–36–42、27–36–42、15–41–48、37–38–42、8–24–47、28–42–49、7–36–41=各10元"
+
+	result := newTestParser().ParseBetString(BetParseRequest{Input: input})
+	if len(result.Ambiguities) != 1 {
+		t.Fatalf("Ambiguities = %d, want 1", len(result.Ambiguities))
+	}
+	ambiguity := result.Ambiguities[0]
+	if ambiguity.Selected != -1 {
+		t.Errorf("Selected = %d, want -1", ambiguity.Selected)
+	}
+	choice := -1
+	for i, interpretation := range ambiguity.Interpretations {
+		if interpretation.TotalGroups == 13 && interpretation.TotalAmount.Equal(decimal.NewFromInt(130)) {
+			choice = i
+		}
+	}
+	if choice < 0 {
+		t.Fatalf("没有13组三中三的理解: %+v", ambiguity.Interpretations)
+	}
+
+	result = newTestParser().ParseBetString(BetParseRequest{Input: input, InterpretationChoices: map[int]int{1: choice}})
+	if result.HasError {
+		t.Fatalf("选择理解后仍报错: %v", result.ErrorMessages)
+	}
+	if result.RoundStatistics.TotalGroups != 13 || !result.RoundStatistics.TotalAmount.Equal(decimal.NewFromInt(130)) {
+		t.Errorf("选择理解后为%d组%s元，want 13组130元", result.RoundStatistics.TotalGroups, result.RoundStatistics.TotalAmount)
+	}
+}
+
+func TestBackfillSegmentAmounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		amounts  []string // 各笔下注的金额
+		warnings []int    // 各笔下注的提示信息条数
+		wantErr  bool
+	}{
+		{
+			name:     "拖码沿用下一行的金额",
+			input:    "三中三2.3.4拖5.6.7拖10.11.12\n三中三1.2.3.4各5",
+			amounts:  []string{"135", "20"},
+			warnings: []int{1, 0},
+		},
+		{
+			name:     "连续多笔沿用后续第一个金额",
+			input:    "二中二1.2.3\n二中二4.5.6\n二中二7.8各10",
+			amounts:  []string{"30", "30", "10"},
+			warnings: []int{1, 1, 0},
+		},
+		{
+			name:     "已标明金额的下注不回填",
+			input:    "二中二1.2.3各5\n二中二4.5.6各10",
+			amounts:  []string{"15", "30"},
+			warnings: []int{0, 0},
+		},
+		{
+			name:     "后面没有金额时报错",
+			input:    "二中二1.2各5\n二中二4.5.6",
+			amounts:  []string{"5", "0"},
+			warnings: []int{0, 0},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError != tt.wantErr {
+				t.Fatalf("HasError = %v, want %v, errors: %v", result.HasError, tt.wantErr, result.ErrorMessages)
+			}
+			if len(result.ParsedBets) != len(tt.amounts) {
+				t.Fatalf("解析为%d笔下注, want %d", len(result.ParsedBets), len(tt.amounts))
+			}
+			for i, bet := range result.ParsedBets {
+				if !bet.BetStatistics.TotalAmount.Equal(decimal.RequireFromString(tt.amounts[i])) {
+					t.Errorf("第%d笔%q金额 = %s, want %s", i+1, bet.OriginalText, bet.BetStatistics.TotalAmount, tt.amounts[i])
+				}
+				if len(bet.Warnings) != tt.warnings[i] {
+					t.Errorf("第%d笔%q提示信息 = %q, want %d条", i+1, bet.OriginalText, bet.Warnings, tt.warnings[i])
+				}
+			}
+		})
+	}
+}
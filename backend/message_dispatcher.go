@@ -0,0 +1,204 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ledgerIDCounter int64
+
+// LedgerEntry 账本记录：一条下注消息及其解析结果
+type LedgerEntry struct {
+	ID         string           `json:"id"`         // 记录ID
+	Message    IncomingMessage  `json:"message"`    // 原始消息
+	Result     BetParsingResult `json:"result"`     // 解析结果
+	RecordedAt time.Time        `json:"recordedAt"` // 记录时间
+}
+
+// BetLedger 下注账本
+type BetLedger interface {
+	Record(entry LedgerEntry) error
+	Entries() []LedgerEntry
+}
+
+// MemoryLedger 内存账本
+type MemoryLedger struct {
+	mutex   sync.RWMutex
+	entries []LedgerEntry
+}
+
+// NewMemoryLedger 创建内存账本
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{entries: make([]LedgerEntry, 0)}
+}
+
+// Record 记录一条下注
+func (l *MemoryLedger) Record(entry LedgerEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Entries 按记录顺序返回所有记录
+func (l *MemoryLedger) Entries() []LedgerEntry {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	entries := make([]LedgerEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// LedgerFileName 账本文件，每行一条记录，只追加不修改
+const LedgerFileName = "bet_ledger.jsonl"
+
+// getLedgerFilePath 获取账本文件路径，与配置历史位于同一目录
+func getLedgerFilePath() (string, error) {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), LedgerFileName), nil
+}
+
+// FileLedger 文件账本：记录追加到账本文件，创建时读取已有记录
+// 记录中含解析使用的配置版本及生肖号码表，重启后仍可按当时的配置重新解析
+type FileLedger struct {
+	mutex  sync.Mutex
+	path   string
+	memory *MemoryLedger
+}
+
+// NewFileLedger 创建文件账本并读取已有记录，格式错误的行记录日志后跳过
+func NewFileLedger(path string) (*FileLedger, error) {
+	memory := NewMemoryLedger()
+	err := readJSONLines(path, "账本", func(line []byte) error {
+		var entry LedgerEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		memory.entries = append(memory.entries, entry)
+		advanceLedgerID(entry.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FileLedger{path: path, memory: memory}, nil
+}
+
+// Record 记录一条下注，写入账本文件成功后才记入内存
+func (l *FileLedger) Record(entry LedgerEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if err := appendJSONLine(l.path, "账本", entry); err != nil {
+		return err
+	}
+	return l.memory.Record(entry)
+}
+
+// Entries 按记录顺序返回所有记录
+func (l *FileLedger) Entries() []LedgerEntry {
+	return l.memory.Entries()
+}
+
+// advanceLedgerID 读取已有记录后调整记录ID计数，避免重启后新记录与已有记录ID重复
+func advanceLedgerID(id string) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, "ledger_"), 10, 64)
+	if err != nil {
+		return
+	}
+	for {
+		current := atomic.LoadInt64(&ledgerIDCounter)
+		if n <= current || atomic.CompareAndSwapInt64(&ledgerIDCounter, current, n) {
+			return
+		}
+	}
+}
+
+// MessageDispatcher 消息分发器：从各消息来源读取消息，解析下注后记入账本
+type MessageDispatcher struct {
+	newParser func(at time.Time) *IntelligentBetParser // 每条消息使用最新配置创建解析器，at为消息发送时间
+	ledger    BetLedger
+	OnRecord  func(entry LedgerEntry) // 记入账本后的回调，可为空
+}
+
+// NewMessageDispatcher 创建消息分发器
+func NewMessageDispatcher(newParser func(at time.Time) *IntelligentBetParser, ledger BetLedger) *MessageDispatcher {
+	return &MessageDispatcher{newParser: newParser, ledger: ledger}
+}
+
+// Run 启动所有消息来源并分发消息，直到ctx取消或所有来源读取完毕
+// 任一来源启动失败时返回错误，已启动的来源随ctx取消而停止
+func (d *MessageDispatcher) Run(ctx context.Context, sources ...MessageSource) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	channels := make([]<-chan IncomingMessage, 0, len(sources))
+	for _, source := range sources {
+		messages, err := source.Start(ctx)
+		if err != nil {
+			return fmt.Errorf("启动消息来源失败: %s: %v", source.Name(), err)
+		}
+		safeLogger.AppendLog(fmt.Sprintf("消息来源已启动: %s", source.Name()))
+		channels = append(channels, messages)
+	}
+
+	var wg sync.WaitGroup
+	for _, messages := range channels {
+		wg.Add(1)
+		go func(messages <-chan IncomingMessage) {
+			defer wg.Done()
+			for message := range messages {
+				if _, err := d.Dispatch(message); err != nil {
+					safeLogger.AppendLog(fmt.Sprintf("分发消息失败: %v", err))
+				}
+			}
+		}(messages)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Dispatch 解析一条消息并记入账本，不含下注内容的消息跳过并返回nil
+// 按消息发送时间生成生肖号码，补读的旧消息跨农历新年时仍按发送当时的生肖号码解析
+func (d *MessageDispatcher) Dispatch(message IncomingMessage) (*LedgerEntry, error) {
+	defer recoverWithLog("Dispatch")
+
+	at := message.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	parser := d.newParser(at)
+	if !parser.hasBetContent(message.Text) {
+		return nil, nil
+	}
+
+	result := parser.ParseBetString(BetParseRequest{
+		Input:        message.Text,
+		UserSettings: make(map[string]interface{}),
+	})
+	entry := LedgerEntry{
+		ID:         fmt.Sprintf("ledger_%d", atomic.AddInt64(&ledgerIDCounter, 1)),
+		Message:    message,
+		Result:     result,
+		RecordedAt: time.Now(),
+	}
+	if err := d.ledger.Record(entry); err != nil {
+		return nil, fmt.Errorf("记入账本失败: %v", err)
+	}
+	safeLogger.AppendLog(fmt.Sprintf("已记入账本: %s %s %d笔下注, 总金额%s元",
+		message.Chat, message.Sender, result.RoundStatistics.TotalBets, result.RoundStatistics.TotalAmount.String()))
+
+	if d.OnRecord != nil {
+		d.OnRecord(entry)
+	}
+	return &entry, nil
+}
@@ -0,0 +1,115 @@
+package backend
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDispatchUsesMessageTime(t *testing.T) {
+	dispatcher := NewMessageDispatcher(func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 0, at))
+	}, NewMemoryLedger())
+
+	// 2026年农历新年(2月17日)前发送的消息，按2025年的生肖号码解析
+	entry, err := dispatcher.Dispatch(IncomingMessage{
+		Sender: "张三",
+		Time:   time.Date(2026, 2, 16, 21, 0, 0, 0, time.Local),
+		Text:   "三中三1.2.3各5",
+	})
+	if err != nil || entry == nil {
+		t.Fatalf("Dispatch = %v, %v", entry, err)
+	}
+	if got := entry.Result.ZodiacTable.LunarYear; got != 2025 {
+		t.Errorf("生肖号码表年份 = %d, want 2025", got)
+	}
+}
+
+func TestMessageDispatcherRun(t *testing.T) {
+	ledger := NewMemoryLedger()
+	dispatcher := NewMessageDispatcher(func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 0, at))
+	}, ledger)
+	recorded := 0
+	dispatcher.OnRecord = func(entry LedgerEntry) { recorded++ }
+
+	mock := NewMockSource(
+		IncomingMessage{Chat: "群1", Sender: "张三", Time: time.Now(), Text: "三中三1.2.3各10"},
+		IncomingMessage{Chat: "群1", Sender: "李四", Time: time.Now(), Text: "好的"},
+	)
+	reader := NewReaderSource("测试输入", strings.NewReader(`{"chat":"群2","sender":"王五","text":"二中二5.6各20"}
+格式错误的行
+{"chat":"群2","sender":"赵六","text":"谢谢"}
+`))
+	if err := dispatcher.Run(context.Background(), mock, reader); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// 不含下注内容的消息及格式错误的行不记入账本
+	entries := ledger.Entries()
+	if len(entries) != 2 || recorded != 2 {
+		t.Fatalf("账本记录 = %d, 回调 = %d, want 2", len(entries), recorded)
+	}
+	total := decimal.Zero
+	for _, entry := range entries {
+		total = total.Add(entry.Result.RoundStatistics.TotalAmount)
+	}
+	if !total.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("总金额 = %s, want 30", total)
+	}
+}
+
+func TestFileLedgerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+	ledger, err := NewFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher := NewMessageDispatcher(func(at time.Time) *IntelligentBetParser {
+		return NewIntelligentBetParser(newParserConfig(getDefaultSystemConfig(), 7, at))
+	}, ledger)
+	recorded, err := dispatcher.Dispatch(IncomingMessage{
+		Sender: "张三",
+		Time:   time.Date(2026, 2, 16, 21, 0, 0, 0, time.Local),
+		Text:   "三中三1.2.3各5",
+	})
+	if err != nil || recorded == nil {
+		t.Fatalf("Dispatch = %v, %v", recorded, err)
+	}
+
+	// 模拟重启：记录ID计数清零后重新打开账本文件，记录中的配置版本、生肖号码表与金额不变
+	atomic.StoreInt64(&ledgerIDCounter, 0)
+	reloaded, err := NewFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reloaded.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("账本记录 = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.ID != recorded.ID || entry.Result.ConfigVersion != 7 {
+		t.Errorf("记录 = %s 版本%d, want %s 版本7", entry.ID, entry.Result.ConfigVersion, recorded.ID)
+	}
+	if entry.Result.ZodiacTable.LunarYear != 2025 || !slices.Equal(entry.Result.ZodiacTable.Numbers["蛇"], recorded.Result.ZodiacTable.Numbers["蛇"]) {
+		t.Errorf("生肖号码表 = %+v, want %+v", entry.Result.ZodiacTable, recorded.Result.ZodiacTable)
+	}
+	if !entry.Result.RoundStatistics.TotalAmount.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("总金额 = %s, want 5", entry.Result.RoundStatistics.TotalAmount)
+	}
+
+	// 重新打开后新记录的ID不与已有记录重复
+	next, err := NewMessageDispatcher(dispatcher.newParser, reloaded).Dispatch(IncomingMessage{Sender: "李四", Text: "二中二5.6各20"})
+	if err != nil || next == nil {
+		t.Fatalf("Dispatch = %v, %v", next, err)
+	}
+	if next.ID == entry.ID {
+		t.Errorf("新记录ID与已有记录重复: %s", next.ID)
+	}
+}
@@ -0,0 +1,361 @@
+package backend
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IncomingMessage 消息来源产生的一条聊天消息
+type IncomingMessage struct {
+	Chat   string    `json:"chat"`   // 群聊名称
+	Sender string    `json:"sender"` // 发送者昵称
+	Time   time.Time `json:"time"`   // 发送时间
+	Text   string    `json:"text"`   // 消息内容
+}
+
+// MessageSource 消息来源，新的聊天接入方式只需实现此接口，无需修改解析器
+type MessageSource interface {
+	// Name 来源名称，用于日志
+	Name() string
+	// Start 开始读取消息，ctx取消或来源读取完毕时关闭返回的通道
+	Start(ctx context.Context) (<-chan IncomingMessage, error)
+}
+
+// ================================
+// 目录监视：定期读取放入目录的聊天记录文件
+// ================================
+
+// processedDirName 已处理文件移入的子目录
+const processedDirName = "processed"
+
+// processingDirName 正在读取的文件移入的子目录，读取完后再移入processed
+// 读取中途停止时文件留在此目录，下次从中断处继续，已发出的消息不会重复读取
+const processingDirName = "processing"
+
+// progressSuffix 记录正在读取的文件已发出的消息条数，与文件同在processing目录
+const progressSuffix = ".progress"
+
+// DirectorySource 监视目录，读取放入的.txt聊天记录（格式同导出的群聊记录）及.jsonl消息文件
+// 读取前先将文件移入processing子目录，读取完移入processed子目录，群聊名称取文件名
+type DirectorySource struct {
+	Dir      string        // 监视的目录
+	Interval time.Duration // 扫描间隔
+
+	skipped map[string]bool // 无法读取或无法移入processed的文件，本次监视不再读取
+}
+
+// NewDirectorySource 创建目录监视来源
+func NewDirectorySource(dir string) *DirectorySource {
+	return &DirectorySource{Dir: dir, Interval: 2 * time.Second}
+}
+
+// Name 来源名称
+func (s *DirectorySource) Name() string {
+	return "目录 " + s.Dir
+}
+
+// Start 开始监视目录
+func (s *DirectorySource) Start(ctx context.Context) (<-chan IncomingMessage, error) {
+	info, err := os.Stat(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("无法访问监视目录: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("监视路径不是目录: %s", s.Dir)
+	}
+	for _, name := range []string{processingDirName, processedDirName} {
+		if err := os.MkdirAll(filepath.Join(s.Dir, name), 0755); err != nil {
+			return nil, fmt.Errorf("创建%s目录失败: %v", name, err)
+		}
+	}
+	s.skipped = make(map[string]bool)
+
+	messages := make(chan IncomingMessage)
+	go func() {
+		defer recoverWithLog("DirectorySource")
+		defer close(messages)
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			s.scan(ctx, messages)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return messages, nil
+}
+
+// scan 先继续读取上次中断的文件，再按文件名顺序认领并读取新放入的文件
+func (s *DirectorySource) scan(ctx context.Context, messages chan<- IncomingMessage) {
+	processingDir := filepath.Join(s.Dir, processingDirName)
+	for _, name := range listMessageFiles(processingDir, 0) {
+		if !s.readFile(ctx, filepath.Join(processingDir, name), messages) {
+			return
+		}
+	}
+
+	// 跳过可能仍在写入的文件
+	for _, name := range listMessageFiles(s.Dir, s.Interval) {
+		path := filepath.Join(s.Dir, name)
+		claimed := filepath.Join(processingDir, name)
+		if _, err := os.Stat(claimed); err == nil {
+			// 同名文件尚未处理完，等其移入processed后再认领
+			continue
+		}
+		if err := os.Rename(path, claimed); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("认领消息文件失败: %s: %v", path, err))
+			continue
+		}
+		if !s.readFile(ctx, claimed, messages) {
+			return
+		}
+	}
+}
+
+// listMessageFiles 目录中的消息文件名，按文件名排序；minAge大于0时跳过最近修改过的文件
+func listMessageFiles(dir string, minAge time.Duration) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("读取监视目录失败: %v", err))
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".txt" && ext != ".jsonl") {
+			continue
+		}
+		if minAge > 0 {
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < minAge {
+				continue
+			}
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readFile 读取processing目录中的文件，从上次中断处继续发出消息，读取完后移入processed目录
+// ctx取消时返回false，已发出的条数记录在进度文件中
+func (s *DirectorySource) readFile(ctx context.Context, path string, messages chan<- IncomingMessage) bool {
+	if s.skipped[path] {
+		return true
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("读取消息文件失败: %s: %v", path, err))
+		return true
+	}
+	fileMessages, err := readMessageFile(path, info.ModTime())
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("读取消息文件失败，本次监视不再读取: %s: %v", path, err))
+		s.skipped[path] = true
+		return true
+	}
+
+	progressPath := path + progressSuffix
+	for i := readProgress(progressPath); i < len(fileMessages); i++ {
+		select {
+		case messages <- fileMessages[i]:
+		case <-ctx.Done():
+			return false
+		}
+		if err := os.WriteFile(progressPath, []byte(strconv.Itoa(i+1)), 0644); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("记录读取进度失败: %s: %v", path, err))
+		}
+	}
+
+	if err := moveToProcessed(path, s.Dir); err != nil {
+		// 消息已全部发出，留在processing目录，本次监视不再读取，避免重复记入账本
+		safeLogger.AppendLog(fmt.Sprintf("移动已处理文件失败，本次监视不再读取: %s: %v", path, err))
+		s.skipped[path] = true
+		return true
+	}
+	os.Remove(progressPath)
+	return true
+}
+
+// readProgress 读取进度文件记录的已发出消息条数，没有进度文件时为0
+func readProgress(progressPath string) int {
+	data, err := os.ReadFile(progressPath)
+	if err != nil {
+		return 0
+	}
+	sent, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || sent < 0 {
+		safeLogger.AppendLog(fmt.Sprintf("读取进度格式错误，从头读取: %s", progressPath))
+		return 0
+	}
+	return sent
+}
+
+// readMessageFile 读取消息文件：.jsonl每行一条消息，.txt为导出的群聊记录
+// 没有消息头的.txt文件整体作为一条消息
+func readMessageFile(path string, modTime time.Time) ([]IncomingMessage, error) {
+	chat := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(path)) == ".jsonl" {
+		messages := make([]IncomingMessage, 0)
+		err := decodeMessageLines(file, chat, func(message IncomingMessage) bool {
+			messages = append(messages, message)
+			return true
+		})
+		return messages, err
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	chatMessages, _ := parseChatLog(string(content))
+	if len(chatMessages) == 0 && strings.TrimSpace(string(content)) != "" {
+		return []IncomingMessage{{Chat: chat, Time: modTime, Text: strings.TrimSpace(string(content))}}, nil
+	}
+	messages := make([]IncomingMessage, 0, len(chatMessages))
+	for _, message := range chatMessages {
+		messages = append(messages, IncomingMessage{Chat: chat, Sender: message.Sender, Time: message.Time, Text: message.Text})
+	}
+	return messages, nil
+}
+
+// moveToProcessed 将已处理的文件移入监视目录dir的processed子目录，重名时加上时间戳
+func moveToProcessed(path string, dir string) error {
+	target := filepath.Join(dir, processedDirName, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(target)
+		target = fmt.Sprintf("%s_%s%s", strings.TrimSuffix(target, ext), time.Now().Format("20060102150405"), ext)
+	}
+	return os.Rename(path, target)
+}
+
+// ================================
+// 标准输入/JSONL：每行一条JSON消息
+// ================================
+
+// ReaderSource 从输入流逐行读取JSON消息，如{"chat":"群名","sender":"张三","time":"2026-10-17T20:31:05+08:00","text":"三中三1.2.3各10"}
+type ReaderSource struct {
+	name   string
+	reader io.Reader
+}
+
+// NewReaderSource 创建输入流来源
+func NewReaderSource(name string, reader io.Reader) *ReaderSource {
+	return &ReaderSource{name: name, reader: reader}
+}
+
+// NewStdinSource 创建标准输入来源
+func NewStdinSource() *ReaderSource {
+	return NewReaderSource("标准输入", os.Stdin)
+}
+
+// Name 来源名称
+func (s *ReaderSource) Name() string {
+	return s.name
+}
+
+// Start 开始读取输入流，读取完毕时关闭通道
+func (s *ReaderSource) Start(ctx context.Context) (<-chan IncomingMessage, error) {
+	messages := make(chan IncomingMessage)
+	go func() {
+		defer recoverWithLog("ReaderSource")
+		defer close(messages)
+
+		err := decodeMessageLines(s.reader, "", func(message IncomingMessage) bool {
+			select {
+			case messages <- message:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("读取%s失败: %v", s.name, err))
+		}
+	}()
+	return messages, nil
+}
+
+// decodeMessageLines 逐行解析JSON消息，格式错误的行记录日志后跳过
+// 未填写群聊名称、时间时分别使用chat及当前时间；emit返回false时停止读取
+func decodeMessageLines(reader io.Reader, chat string, emit func(IncomingMessage) bool) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var message IncomingMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			safeLogger.AppendLog(fmt.Sprintf("第%d行消息格式错误，已跳过: %v", lineNumber, err))
+			continue
+		}
+		if message.Chat == "" {
+			message.Chat = chat
+		}
+		if message.Time.IsZero() {
+			message.Time = time.Now()
+		}
+		if !emit(message) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// ================================
+// 模拟来源：按顺序产生预设的消息，用于调试
+// ================================
+
+// MockSource 模拟消息来源
+type MockSource struct {
+	messages []IncomingMessage
+}
+
+// NewMockSource 创建模拟消息来源
+func NewMockSource(messages ...IncomingMessage) *MockSource {
+	return &MockSource{messages: messages}
+}
+
+// Name 来源名称
+func (s *MockSource) Name() string {
+	return "模拟来源"
+}
+
+// Start 依次产生预设的消息后关闭通道
+func (s *MockSource) Start(ctx context.Context) (<-chan IncomingMessage, error) {
+	messages := make(chan IncomingMessage)
+	go func() {
+		defer close(messages)
+		for _, message := range s.messages {
+			select {
+			case messages <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return messages, nil
+}
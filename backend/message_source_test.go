@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// receiveMessages 从来源读取count条消息，超时则测试失败
+func receiveMessages(t *testing.T, messages <-chan IncomingMessage, count int) []IncomingMessage {
+	t.Helper()
+	received := make([]IncomingMessage, 0, count)
+	for len(received) < count {
+		select {
+		case message := <-messages:
+			received = append(received, message)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("只收到%d条消息, want %d", len(received), count)
+		}
+	}
+	return received
+}
+
+// waitForFile 等待文件出现，超时则测试失败
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("文件未出现: %s", path)
+}
+
+func TestDirectorySourceClaimsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "群1.jsonl")
+	content := `{"sender":"张三","text":"三中三1.2.3各10"}
+{"sender":"李四","text":"二中二5.6各20"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	os.Chtimes(path, old, old)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := &DirectorySource{Dir: dir, Interval: 10 * time.Millisecond}
+	messages, err := source.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// 读取前已移出监视目录，读取完后移入processed
+	first := receiveMessages(t, messages, 1)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("读取时文件仍在监视目录中")
+	}
+	if first[0].Chat != "群1" || first[0].Sender != "张三" {
+		t.Errorf("消息 = %+v", first[0])
+	}
+	receiveMessages(t, messages, 1)
+	waitForFile(t, filepath.Join(dir, processedDirName, "群1.jsonl"))
+}
+
+func TestDirectorySourceResumesInterruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	processingDir := filepath.Join(dir, processingDirName)
+	if err := os.MkdirAll(processingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 上次读取到第2条时中断
+	path := filepath.Join(processingDir, "群1.jsonl")
+	content := `{"sender":"张三","text":"三中三1.2.3各10"}
+{"sender":"李四","text":"二中二5.6各20"}
+{"sender":"王五","text":"三中三7.8.9各5"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+progressSuffix, []byte("2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := &DirectorySource{Dir: dir, Interval: 10 * time.Millisecond}
+	messages, err := source.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	received := receiveMessages(t, messages, 1)
+	if received[0].Sender != "王五" {
+		t.Errorf("应从第3条继续读取, got %+v", received[0])
+	}
+	waitForFile(t, filepath.Join(dir, processedDirName, "群1.jsonl"))
+	if _, err := os.Stat(path + progressSuffix); !os.IsNotExist(err) {
+		t.Errorf("读取完后进度文件未删除")
+	}
+	select {
+	case message := <-messages:
+		t.Errorf("已发出的消息被重复读取: %+v", message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
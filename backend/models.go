@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -40,49 +41,55 @@ type LotteryResult struct {
 
 // SystemConfig 系统配置
 type SystemConfig struct {
-	ZodiacConfig   ZodiacConfig   `json:"zodiac_config"`    // 12生肖配置
-	ColorConfig    ColorConfig    `json:"color_config"`     // 颜色波段配置
-	TailConfig     TailConfig     `json:"tail_config"`      // 尾数配置
-	BetTypeAliases BetTypeAliases `json:"bet_type_aliases"` // 下注类型别名配置
-	KeywordAliases KeywordAliases `json:"keyword_aliases"`  // 关键字别名配置
-	OddsConfig     OddsConfig     `json:"odds_config"`      // 赔率配置
-}
-
-// ZodiacConfig 12生肖配置
-type ZodiacConfig struct {
-	Rat     []int `json:"rat"`     // 鼠
-	Ox      []int `json:"ox"`      // 牛
-	Tiger   []int `json:"tiger"`   // 虎
-	Rabbit  []int `json:"rabbit"`  // 兔
-	Dragon  []int `json:"dragon"`  // 龙
-	Snake   []int `json:"snake"`   // 蛇
-	Horse   []int `json:"horse"`   // 马
-	Goat    []int `json:"goat"`    // 羊
-	Monkey  []int `json:"monkey"`  // 猴
-	Rooster []int `json:"rooster"` // 鸡
-	Dog     []int `json:"dog"`     // 狗
-	Pig     []int `json:"pig"`     // 猪
-}
-
-// ColorConfig 颜色波段配置
-type ColorConfig struct {
-	Red   []int `json:"red"`   // 红波
-	Green []int `json:"green"` // 绿波
-	Blue  []int `json:"blue"`  // 蓝波
-}
-
-// TailConfig 尾数配置 (0-9尾)
-type TailConfig struct {
-	Tail0 []int `json:"tail_0"` // 0尾
-	Tail1 []int `json:"tail_1"` // 1尾
-	Tail2 []int `json:"tail_2"` // 2尾
-	Tail3 []int `json:"tail_3"` // 3尾
-	Tail4 []int `json:"tail_4"` // 4尾
-	Tail5 []int `json:"tail_5"` // 5尾
-	Tail6 []int `json:"tail_6"` // 6尾
-	Tail7 []int `json:"tail_7"` // 7尾
-	Tail8 []int `json:"tail_8"` // 8尾
-	Tail9 []int `json:"tail_9"` // 9尾
+	SchemaVersion  int             `json:"schema_version"`   // 配置文件结构版本，加载旧版本文件时逐步升级
+	ActiveProfile  string          `json:"active_profile"`   // 当前配置方案名称，号码集合、别名、赔率为该方案的配置
+	Profiles       []ConfigProfile `json:"profiles"`         // 其他配置方案
+	NumberSets     []NumberSet     `json:"number_sets"`      // 号码集合（生肖、波色、尾数等）
+	ZodiacSchedule ZodiacSchedule  `json:"zodiac_schedule"`  // 生肖换肖设置
+	BetTypeAliases BetTypeAliases  `json:"bet_type_aliases"` // 下注类型别名配置
+	KeywordAliases KeywordAliases  `json:"keyword_aliases"`  // 关键字别名配置
+	OddsConfig     OddsConfig      `json:"odds_config"`      // 赔率配置
+	ParserOptions  ParserOptions   `json:"parser_options"`   // 解析选项
+	ReplyTemplates ReplyTemplates  `json:"reply_templates"`  // 回复模板
+
+	// 配置文件中当前版本不认识的配置项（如更高版本程序写入的），保存时原样写回
+	unknownFields map[string]json.RawMessage
+}
+
+// ConfigProfile 配置方案：不同微信群使用各自的号码集合、别名及赔率
+type ConfigProfile struct {
+	Name           string         `json:"name"`             // 方案名称，如群名
+	NumberSets     []NumberSet    `json:"number_sets"`      // 号码集合
+	BetTypeAliases BetTypeAliases `json:"bet_type_aliases"` // 下注类型别名
+	KeywordAliases KeywordAliases `json:"keyword_aliases"`  // 关键字别名
+	OddsConfig     OddsConfig     `json:"odds_config"`      // 赔率
+}
+
+// ProfileInfo 配置方案概要
+type ProfileInfo struct {
+	Name   string `json:"name"`   // 方案名称
+	Active bool   `json:"active"` // 是否为当前方案
+}
+
+// NumberSet 号码集合：生肖、波色、尾数等可在下注中代替号码的名称
+type NumberSet struct {
+	Name     string   `json:"name"`     // 名称，如"鼠"、"红波"、"0尾"
+	Category string   `json:"category"` // 分类，如"生肖"、"波色"，用于界面分组
+	Aliases  []string `json:"aliases"`  // 别名，如红波的"红"、"红色"
+	Numbers  []int    `json:"numbers"`  // 包含的号码
+}
+
+// ZodiacSchedule 生肖换肖设置：每年农历新年起，本命生肖为1、13、25、37、49
+type ZodiacSchedule struct {
+	AutoUpdate   bool              `json:"auto_update"`    // 按新年日期自动更新生肖号码，关闭时使用号码集合中配置的生肖号码
+	NewYearDates map[string]string `json:"new_year_dates"` // 农历新年日期，key: 公历年份 value: "2006-01-02"
+}
+
+// ZodiacTable 生肖号码表
+type ZodiacTable struct {
+	LunarYear  int              `json:"lunarYear"`  // 农历年份，使用号码集合中配置的生肖号码时为0
+	YearZodiac string           `json:"yearZodiac"` // 本命生肖（号码1所属的生肖）
+	Numbers    map[string][]int `json:"numbers"`    // key: 生肖 value: 号码
 }
 
 // BetTypeAliases 下注类型别名配置
@@ -148,6 +155,72 @@ type SpecialOdds struct {
 	Rebate    float64 `json:"rebate"`     // 回水率
 }
 
+// ParserOptions 解析选项
+type ParserOptions struct {
+	StrictUnknownText bool                `json:"strict_unknown_text"` // 严格模式：存在无法识别的中文时视为解析错误，而不是忽略并提示
+	WeChatFilter      WeChatFilterOptions `json:"wechat_filter"`       // 微信消息噪声过滤
+	// 玩家标识（正则表达式），第一个捕获分组为玩家昵称；输入按玩家标识拆分，各玩家分别解析
+	PlayerHeaderPatterns []string `json:"player_header_patterns"`
+}
+
+// WeChatFilterOptions 微信消息噪声过滤选项，开启的项目在解析前从输入中去除
+type WeChatFilterOptions struct {
+	QuotedReplies bool `json:"quoted_replies"` // 引用回复「张三：…」及分隔线
+	Mentions      bool `json:"mentions"`       // @提及
+	EmojiCodes    bool `json:"emoji_codes"`    // 表情代码，如[强]、[玫瑰]
+	RecallNotices bool `json:"recall_notices"` // 撤回消息提示
+	SenderHeaders bool `json:"sender_headers"` // 发送者及时间行，如"张三 2026-10-17 20:31:05"
+}
+
+// ReplyTemplates 回复模板（Go text/template语法），为空时使用默认模板
+// 可用字段：.Player .Lines(.Lottery .BetType .Mode .Groups .UnitAmount .Amount .Text) .Stats(.Lottery .BetType .Groups .Count .Amount)
+// .TotalAmount .TotalGroups .TotalBets .Errors .Warnings .Payout .Profit，函数：join
+type ReplyTemplates struct {
+	Accepted  string `json:"accepted"`  // 收单确认，如"收到 新澳 三中三 10组×20=200 共200"
+	Rejected  string `json:"rejected"`  // 拒收，附错误原因
+	Settled   string `json:"settled"`   // 结算
+	Statement string `json:"statement"` // 对账单
+}
+
+// ReplyRenderRequest 回复生成请求
+type ReplyRenderRequest struct {
+	Kind   string           `json:"kind"`   // 回复类型：accepted/rejected/settled/statement，为空时按解析结果选择accepted或rejected
+	Result BetParsingResult `json:"result"` // 一轮下注的解析结果
+	Payout decimal.Decimal  `json:"payout"` // 中奖金额，结算及对账单使用
+}
+
+// ConfigVersion 配置版本：每次保存配置时记录，用于按当时的配置重新解析、结算旧的轮次
+type ConfigVersion struct {
+	Version     int          `json:"version"`     // 版本号，从1开始递增
+	EffectiveAt time.Time    `json:"effectiveAt"` // 生效时间
+	Config      SystemConfig `json:"config"`      // 该版本的完整配置
+}
+
+// ConfigVersionInfo 配置版本概要
+type ConfigVersionInfo struct {
+	Version     int       `json:"version"`     // 版本号
+	EffectiveAt time.Time `json:"effectiveAt"` // 生效时间
+}
+
+// ConfigAuditEntry 配置修改审计记录：每次修改配置时记录修改的内容及操作员，可按记录撤销
+type ConfigAuditEntry struct {
+	ID            int            `json:"id"`            // 记录ID，从1开始递增
+	Section       string         `json:"section"`       // 修改的配置，如"赔率配置"
+	Operator      string         `json:"operator"`      // 操作员
+	Timestamp     time.Time      `json:"timestamp"`     // 修改时间
+	ConfigVersion int            `json:"configVersion"` // 修改后的配置版本
+	Changes       []ConfigChange `json:"changes"`       // 修改前后有变化的配置项
+	RevertOf      int            `json:"revertOf"`      // 撤销的审计记录ID，0表示不是撤销
+}
+
+// ConfigBackupInfo 配置备份文件概要
+type ConfigBackupInfo struct {
+	Name      string    `json:"name"`      // 备份文件名
+	Reason    string    `json:"reason"`    // 备份原因：save保存前、restore恢复前、corrupt无法解析、schemaN升级前
+	CreatedAt time.Time `json:"createdAt"` // 备份时间
+	Size      int64     `json:"size"`      // 文件大小（字节）
+}
+
 // ================================
 // 解析引擎相关模型
 // ================================
@@ -157,6 +230,8 @@ type BetParseRequest struct {
 	Input        string                 `json:"input"`         // 输入的下注字符串
 	EnabledTypes []string               `json:"enabled_types"` // 启用的彩种类型
 	UserSettings map[string]interface{} `json:"user_settings"` // 用户设置
+	// 操作员为存在歧义的下注选择的理解方式 key: 下注序号（从1开始） value: 理解方式下标
+	InterpretationChoices map[int]int `json:"interpretation_choices"`
 }
 
 // BetParseResponse 解析响应
@@ -199,22 +274,55 @@ type BetTypeSummary struct {
 // BetParsingResult 整轮下注解析结果
 type BetParsingResult struct {
 	RoundID         string             `json:"roundId"`         // 轮次ID (递增数字)
+	Player          string             `json:"player"`          // 玩家昵称，按玩家拆分时的各玩家轮次才有
 	OriginalText    string             `json:"originalText"`    // 原始下注文本
 	ParsedBets      []SingleBetParsing `json:"parsedBets"`      // 每笔下注解析结果
 	RoundStatistics RoundBetStatistics `json:"roundStatistics"` // 整轮统计信息
 	ParseTime       time.Time          `json:"parseTime"`       // 解析时间
 	HasError        bool               `json:"hasError"`        // 是否有错误
 	ErrorMessages   []string           `json:"errorMessages"`   // 错误信息列表
+	WarningMessages []string           `json:"warningMessages"` // 提示信息列表（需操作员确认）
+	Ambiguities     []BetAmbiguity     `json:"ambiguities"`     // 存在多种理解方式的下注，供操作员在纠错窗口中选择
+	// 输入中含玩家标识（如"张三："）时，各玩家单独解析的轮次；整段结果汇总所有玩家的下注
+	PlayerRounds  []BetParsingResult `json:"playerRounds"`
+	ZodiacTable   ZodiacTable        `json:"zodiacTable"`   // 本轮解析使用的生肖号码表
+	ConfigVersion int                `json:"configVersion"` // 本轮解析使用的配置版本，0表示未记录
+	Profile       string             `json:"profile"`       // 本轮解析使用的配置方案
+}
+
+// BetAmbiguity 单笔下注的多种理解方式
+type BetAmbiguity struct {
+	BetID           string              `json:"betId"`           // 对应的下注ID
+	BetIndex        int                 `json:"betIndex"`        // 对应的下注序号（从1开始）
+	OriginalText    string              `json:"originalText"`    // 分段后的下注文本
+	Interpretations []BetInterpretation `json:"interpretations"` // 各理解方式，按置信度降序排列
+	Selected        int                 `json:"selected"`        // 实际采用的理解方式下标，按原文无法解析且操作员未选择时为-1
+}
+
+// BetInterpretation 单种理解方式
+type BetInterpretation struct {
+	Label       string          `json:"label"`       // 理解方式说明
+	Text        string          `json:"text"`        // 按该理解改写后的下注文本
+	Confidence  float64         `json:"confidence"`  // 置信度（0-1，同一笔下注内合计为1）
+	TotalGroups int             `json:"totalGroups"` // 按该理解解析的总组数
+	TotalAmount decimal.Decimal `json:"totalAmount"` // 按该理解解析的总金额
+	NeedAmount  bool            `json:"needAmount"`  // 按该理解缺少金额，选择后须补充金额
 }
 
 // SingleBetParsing 单笔下注解析结果
 type SingleBetParsing struct {
 	BetID         string                    `json:"betId"`         // 下注ID
+	Player        string                    `json:"player"`        // 玩家昵称，输入中含玩家标识时才有
 	OriginalText  string                    `json:"originalText"`  // 原始下注文本
 	LotteryBets   map[string]LotteryBetInfo `json:"lotteryBets"`   // 各体彩下注信息 key: 体彩类型("新澳"/"老澳"/"香港")
 	BetStatistics BetStatistics             `json:"betStatistics"` // 本笔下注统计
 	HasError      bool                      `json:"hasError"`      // 是否有错误
 	ErrorMessage  []string                  `json:"errorMessage"`  // 错误信息
+	Warnings      []string                  `json:"warnings"`      // 提示信息（如沿用后续金额），需操作员确认
+	// 玩家声明的组数与合计（如"27组"、"共540"），用于与解析结果核对
+	DeclaredGroups int             `json:"declaredGroups"` // 声明组数，0表示未声明
+	DeclaredTotal  decimal.Decimal `json:"declaredTotal"`  // 声明合计，0表示未声明
+	IgnoredText    []string        `json:"ignoredText"`    // 解析时被忽略的中文片段（如"块"、"谢谢"）
 }
 
 // BetTypeFlags 下注类型标识（英文变量名）
@@ -295,28 +403,36 @@ type BetTypeStat struct {
 
 // IntelligentBetParserConfig 智能解析器配置
 type IntelligentBetParserConfig struct {
-	ZodiacMap      map[string][]int    `json:"zodiacMap"`      // 生肖映射
-	ColorMap       map[string][]int    `json:"colorMap"`       // 颜色映射
-	TailMap        map[string][]int    `json:"tailMap"`        // 尾数映射
+	NumberSets     []NumberSet         `json:"numberSets"`     // 号码集合
+	ZodiacTable    ZodiacTable         `json:"zodiacTable"`    // 生肖号码表，已应用到号码集合
+	ConfigVersion  int                 `json:"configVersion"`  // 配置版本，记录到解析结果中
+	Profile        string              `json:"profile"`        // 配置方案，记录到解析结果中
 	BetTypeAliases map[string][]string `json:"betTypeAliases"` // 下注类型别名
 	LotteryAliases map[string][]string `json:"lotteryAliases"` // 体彩别名
 	KeywordAliases map[string][]string `json:"keywordAliases"` // 关键字别名
 	EndKeywords    map[string][]string `json:"endKeywords"`    // 结束关键词
+	// 严格模式：存在无法识别的中文时视为解析错误
+	StrictUnknownText bool `json:"strictUnknownText"`
+	// 微信消息噪声过滤
+	WeChatFilter WeChatFilterOptions `json:"weChatFilter"`
+	// 玩家标识
+	PlayerHeaderPatterns []string `json:"playerHeaderPatterns"`
 }
 
-// AmountMatch 金额匹配位置,用来分割下注使用
-type AmountMatch struct {
-    Start int
-    End   int
+// BetSegment 分段后的单笔下注文本
+type BetSegment struct {
+	Tokens   []Token  // 下注的词法单元
+	Text     string   // 规范化后的下注文本
+	Warnings []string // 分段阶段产生的提示信息
+	Ignored  []string // 该笔下注中被忽略的文字
 }
 
-
 // BetContext 下注上下文
 type BetContext struct {
 	inheritedLotteries []string // 继承的体彩类型
+	inheritedBetTypes  []string // 继承的下注类型，本笔没写下注类型时沿用
 }
 
-
 // NumbersAndAmount 号码和金额结构
 type NumbersAndAmount struct {
 	Numbers []int
@@ -328,3 +444,39 @@ type NumberGroupsAndAmount struct {
 	NumberGroups [][]int
 	Amount       float64
 }
+
+// ================================
+// 聊天记录导入相关模型
+// ================================
+
+// ChatMessage 聊天记录中的一条消息
+type ChatMessage struct {
+	Sender string    `json:"sender"` // 发送者昵称
+	Time   time.Time `json:"time"`   // 发送时间
+	Text   string    `json:"text"`   // 消息内容（可多行）
+}
+
+// ChatImportOptions 聊天记录导入选项
+type ChatImportOptions struct {
+	DrawCutoff   string   `json:"draw_cutoff"`   // 每期截止时间，如"21:30"，之后的消息计入下一期，为空时使用默认值
+	EnabledTypes []string `json:"enabled_types"` // 启用的下注类型
+	Profile      string   `json:"profile"`       // 使用的配置方案，为空时使用当前方案
+}
+
+// PlayerBetBatch 单个玩家在一期内的下注
+type PlayerBetBatch struct {
+	Player   string           `json:"player"`   // 玩家昵称
+	Period   string           `json:"period"`   // 期数（开奖日期，如"2026-10-17"）
+	Messages []ChatMessage    `json:"messages"` // 该玩家在该期内的下注消息
+	Result   BetParsingResult `json:"result"`   // 合并解析结果
+}
+
+// ChatImportResult 聊天记录导入结果
+type ChatImportResult struct {
+	Batches         []PlayerBetBatch `json:"batches"`         // 按期数、玩家分组的下注
+	MessageCount    int              `json:"messageCount"`    // 消息总数
+	SkippedMessages []ChatMessage    `json:"skippedMessages"` // 不含下注内容的消息
+	TotalAmount     decimal.Decimal  `json:"totalAmount"`     // 所有下注的总金额
+	HasError        bool             `json:"hasError"`        // 是否有解析错误的下注
+	WarningMessages []string         `json:"warningMessages"` // 提示信息，如第一条消息前无法归属的内容
+}
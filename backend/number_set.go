@@ -0,0 +1,255 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 号码集合分类
+const (
+	NumberSetZodiac   = "生肖"
+	NumberSetColor    = "波色"
+	NumberSetTail     = "尾数"
+	NumberSetHead     = "头数"
+	NumberSetSize     = "大小"
+	NumberSetParity   = "单双"
+	NumberSetDigitSum = "合数"
+	NumberSetAnimal   = "家禽野兽"
+	NumberSetElement  = "五行"
+	NumberSetAll      = "全场"
+)
+
+// 12生肖的顺序
+var zodiacNames = []string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+
+// defaultNumberSets 默认号码集合
+func defaultNumberSets() []NumberSet {
+	// 生肖号码按当前农历年份生成，本命生肖为1、13、25、37、49
+	zodiacNumbers := zodiacNumbersForYear(lunarYearAt(time.Now(), defaultLunarNewYears))
+
+	sets := make([]NumberSet, 0)
+	for _, name := range zodiacNames {
+		sets = append(sets, NumberSet{Name: name, Category: NumberSetZodiac, Aliases: []string{}, Numbers: zodiacNumbers[name]})
+	}
+
+	sets = append(sets,
+		NumberSet{Name: "红波", Category: NumberSetColor, Aliases: []string{"红", "红色"},
+			Numbers: []int{1, 2, 7, 8, 12, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46}},
+		NumberSet{Name: "绿波", Category: NumberSetColor, Aliases: []string{"绿", "绿色"},
+			Numbers: []int{5, 6, 11, 16, 17, 21, 22, 27, 28, 32, 33, 38, 39, 43, 44, 49}},
+		NumberSet{Name: "蓝波", Category: NumberSetColor, Aliases: []string{"蓝", "蓝色"},
+			Numbers: []int{3, 4, 9, 10, 14, 15, 20, 25, 26, 31, 36, 37, 41, 42, 47, 48}},
+	)
+
+	for tail := 0; tail <= 9; tail++ {
+		sets = append(sets, NumberSet{Name: fmt.Sprintf("%d尾", tail), Category: NumberSetTail, Aliases: []string{},
+			Numbers: numbersWhere(func(n int) bool { return n%10 == tail })})
+	}
+	for head := 0; head <= 4; head++ {
+		sets = append(sets, NumberSet{Name: fmt.Sprintf("%d头", head), Category: NumberSetHead, Aliases: []string{},
+			Numbers: numbersWhere(func(n int) bool { return n/10 == head })})
+	}
+
+	sets = append(sets,
+		NumberSet{Name: "大", Category: NumberSetSize, Aliases: []string{"大数"}, Numbers: numbersWhere(func(n int) bool { return n >= 25 })},
+		NumberSet{Name: "小", Category: NumberSetSize, Aliases: []string{"小数"}, Numbers: numbersWhere(func(n int) bool { return n <= 24 })},
+		NumberSet{Name: "单", Category: NumberSetParity, Aliases: []string{"单数"}, Numbers: numbersWhere(func(n int) bool { return n%2 == 1 })},
+		NumberSet{Name: "双", Category: NumberSetParity, Aliases: []string{"双数"}, Numbers: numbersWhere(func(n int) bool { return n%2 == 0 })},
+		NumberSet{Name: "合单", Category: NumberSetDigitSum, Aliases: []string{"合数单"}, Numbers: numbersWhere(func(n int) bool { return (n/10+n%10)%2 == 1 })},
+		NumberSet{Name: "合双", Category: NumberSetDigitSum, Aliases: []string{"合数双"}, Numbers: numbersWhere(func(n int) bool { return (n/10+n%10)%2 == 0 })},
+	)
+
+	// 家禽、野兽由生肖组成
+	animals := func(names ...string) []int {
+		numbers := make([]int, 0)
+		for _, name := range names {
+			numbers = append(numbers, zodiacNumbers[name]...)
+		}
+		slices.Sort(numbers)
+		return numbers
+	}
+	sets = append(sets,
+		NumberSet{Name: "家禽", Category: NumberSetAnimal, Aliases: []string{"家畜"}, Numbers: animals(animalZodiacs["家禽"]...)},
+		NumberSet{Name: "野兽", Category: NumberSetAnimal, Aliases: []string{"野兽肖"}, Numbers: animals(animalZodiacs["野兽"]...)},
+	)
+
+	sets = append(sets, elementNumberSets(lunarYearAt(time.Now(), defaultLunarNewYears))...)
+
+	sets = append(sets, NumberSet{Name: "全场", Category: NumberSetAll, Aliases: []string{"全号"}, Numbers: numbersWhere(isValidLotteryNumber)})
+
+	return sets
+}
+
+// elementNumberSets 农历年份的五行号码集合，按金木水火土排列
+func elementNumberSets(year int) []NumberSet {
+	elementNumbers := elementNumbersForYear(year)
+	sets := make([]NumberSet, 0, len(elementNames))
+	for _, name := range elementNames {
+		sets = append(sets, NumberSet{Name: name, Category: NumberSetElement, Aliases: []string{}, Numbers: elementNumbers[name]})
+	}
+	return sets
+}
+
+// numbersWhere 返回1-49中满足条件的号码
+func numbersWhere(match func(n int) bool) []int {
+	numbers := make([]int, 0)
+	for n := 1; n <= 49; n++ {
+		if match(n) {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+// numberSetsByCategory 返回指定分类的号码集合
+func numberSetsByCategory(sets []NumberSet, category string) []NumberSet {
+	result := make([]NumberSet, 0)
+	for _, set := range sets {
+		if set.Category == category {
+			result = append(result, set)
+		}
+	}
+	return result
+}
+
+// ================================
+// 旧版配置：生肖、波色、尾数各自为固定结构，仅用于迁移旧配置文件
+// ================================
+
+// legacyZodiacConfig 旧版12生肖配置
+type legacyZodiacConfig struct {
+	Rat     []int `json:"rat"`
+	Ox      []int `json:"ox"`
+	Tiger   []int `json:"tiger"`
+	Rabbit  []int `json:"rabbit"`
+	Dragon  []int `json:"dragon"`
+	Snake   []int `json:"snake"`
+	Horse   []int `json:"horse"`
+	Goat    []int `json:"goat"`
+	Monkey  []int `json:"monkey"`
+	Rooster []int `json:"rooster"`
+	Dog     []int `json:"dog"`
+	Pig     []int `json:"pig"`
+}
+
+// legacyColorConfig 旧版颜色波段配置
+type legacyColorConfig struct {
+	Red   []int `json:"red"`
+	Green []int `json:"green"`
+	Blue  []int `json:"blue"`
+}
+
+// legacyNumberSetFields 旧版配置文件中与号码集合相关的字段
+type legacyNumberSetFields struct {
+	ZodiacConfig *legacyZodiacConfig `json:"zodiac_config"`
+	ColorConfig  *legacyColorConfig  `json:"color_config"`
+	TailConfig   map[string][]int    `json:"tail_config"` // key: tail_0 ~ tail_9
+}
+
+// migrateLegacyNumberSets 配置文件升级：将旧版的生肖、波色、尾数配置迁移到号码集合并删除旧字段
+// 旧配置中没有的号码使用默认号码集合；配置文件中已有号码集合时只删除旧字段
+func migrateLegacyNumberSets(raw map[string]json.RawMessage) error {
+	legacyData, err := json.Marshal(map[string]json.RawMessage{
+		"zodiac_config": raw["zodiac_config"],
+		"color_config":  raw["color_config"],
+		"tail_config":   raw["tail_config"],
+	})
+	if err != nil {
+		return err
+	}
+	delete(raw, "zodiac_config")
+	delete(raw, "color_config")
+	delete(raw, "tail_config")
+	if _, ok := raw["number_sets"]; ok {
+		return nil
+	}
+
+	var legacy legacyNumberSetFields
+	if err := json.Unmarshal(legacyData, &legacy); err != nil {
+		return fmt.Errorf("旧版生肖、波色、尾数配置格式错误: %v", err)
+	}
+
+	legacyNumbers := make(map[string][]int)
+	if zodiac := legacy.ZodiacConfig; zodiac != nil {
+		for i, numbers := range [][]int{zodiac.Rat, zodiac.Ox, zodiac.Tiger, zodiac.Rabbit, zodiac.Dragon, zodiac.Snake,
+			zodiac.Horse, zodiac.Goat, zodiac.Monkey, zodiac.Rooster, zodiac.Dog, zodiac.Pig} {
+			legacyNumbers[zodiacNames[i]] = numbers
+		}
+	}
+	if color := legacy.ColorConfig; color != nil {
+		legacyNumbers["红波"] = color.Red
+		legacyNumbers["绿波"] = color.Green
+		legacyNumbers["蓝波"] = color.Blue
+	}
+	for key, numbers := range legacy.TailConfig {
+		legacyNumbers[strings.TrimPrefix(key, "tail_")+"尾"] = numbers
+	}
+
+	sets := defaultNumberSets()
+	for i, set := range sets {
+		if numbers, ok := legacyNumbers[set.Name]; ok && len(numbers) > 0 {
+			sets[i].Numbers = numbers
+		}
+	}
+	data, err := json.Marshal(sets)
+	if err != nil {
+		return err
+	}
+	raw["number_sets"] = data
+	return nil
+}
+
+// migrateElementNumberSets 配置文件升级：号码集合及各配置方案的号码集合中没有五行时加入默认的五行号码集合
+// 已有同名号码集合或五行分类时视为用户已自行配置，不做修改
+func migrateElementNumberSets(raw map[string]json.RawMessage) error {
+	year := lunarYearAt(time.Now(), defaultLunarNewYears)
+	if data, ok := raw["number_sets"]; ok {
+		updated, err := appendElementNumberSets(data, year)
+		if err != nil {
+			return err
+		}
+		raw["number_sets"] = updated
+	}
+
+	data, ok := raw["profiles"]
+	if !ok {
+		return nil
+	}
+	var profiles []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		// 格式错误的配置方案在加载时报错，这里不做处理
+		return nil
+	}
+	for _, profile := range profiles {
+		if sets, ok := profile["number_sets"]; ok {
+			updated, err := appendElementNumberSets(sets, year)
+			if err != nil {
+				return err
+			}
+			profile["number_sets"] = updated
+		}
+	}
+	updated, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	raw["profiles"] = updated
+	return nil
+}
+
+// appendElementNumberSets 号码集合中没有五行时加入，格式错误时原样返回
+func appendElementNumberSets(data json.RawMessage, year int) (json.RawMessage, error) {
+	var sets []NumberSet
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return data, nil
+	}
+	for _, set := range sets {
+		if set.Category == NumberSetElement || slices.Contains(elementNames, set.Name) {
+			return data, nil
+		}
+	}
+	return json.Marshal(append(sets, elementNumberSets(year)...))
+}
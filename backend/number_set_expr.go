@@ -0,0 +1,236 @@
+package backend
+
+import (
+	"slices"
+	"strconv"
+	"unicode"
+)
+
+// 号码集合运算
+const (
+	setOpExclude = "去"  // 中缀：A去B为A中去掉B的号码，如"红波去12"
+	setOpExcept  = "除外" // 后缀：A除外B为B中去掉A的号码，省略B时为全部号码，如"鼠牛除外全场"
+)
+
+// setOperatorKeywords 集合运算关键词
+var setOperatorKeywords = map[string]string{
+	"去":  setOpExclude,
+	"去掉": setOpExclude,
+	"除":  setOpExclude,
+	"除去": setOpExclude,
+	"不要": setOpExclude,
+	"除外": setOpExcept,
+}
+
+// foldSetExpressions 计算号码集合表达式，将表达式合并为一个号码集合词法单元，原文保留在Value中
+//   - 直接相连的号码集合：同一分类取并集（如"鼠牛"），不同分类取交集（如"红单"为红波中的单数）
+//   - 去、除、不要：从前面的号码中去掉后面的号码，如"红波去12"、"绿波双不要5尾"
+//   - 除外：从后面的号码（省略时为全部号码）中去掉前面的号码，如"鼠牛除外全场"
+//
+// 无法组成表达式的运算关键词视为无法识别的文字；结果为空的表达式同样视为无法识别的文字
+func (p *IntelligentBetParser) foldSetExpressions(text string, tokens []Token) []Token {
+	tokens = foldAdjacentSets(text, tokens)
+
+	folded := make([]Token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Type == TokenUnknown {
+			folded = appendUnknown(folded, token)
+			continue
+		}
+		if token.Type != TokenSetOperator {
+			folded = append(folded, token)
+			continue
+		}
+
+		// 左侧运算对象：紧邻（可隔空白）的号码组
+		left := len(folded) - 1
+		for left >= 0 && isBlankToken(folded[left]) {
+			left--
+		}
+		leftStart := -1
+		if left >= 0 && folded[left].isNumeric() {
+			leftStart = numericChainStart(folded, left)
+		}
+
+		// 右侧运算对象
+		right := i + 1
+		for right < len(tokens) && isBlankToken(tokens[right]) {
+			right++
+		}
+		rightEnd := -1
+		if right < len(tokens) && tokens[right].isNumeric() {
+			rightEnd = numericChainEnd(tokens, right)
+		}
+
+		var numbers []int
+		start, end := 0, token.End
+		switch {
+		case token.Value == setOpExclude && leftStart >= 0 && rightEnd >= 0:
+			numbers = subtractNumbers(chainNumbers(folded[leftStart:left+1]), chainNumbers(tokens[right:rightEnd+1]))
+			start, end = folded[leftStart].Start, tokens[rightEnd].End
+			i = rightEnd
+		case token.Value == setOpExcept && leftStart >= 0:
+			universe := numbersWhere(isValidLotteryNumber)
+			if rightEnd >= 0 {
+				universe = chainNumbers(tokens[right : rightEnd+1])
+				end = tokens[rightEnd].End
+				i = rightEnd
+			}
+			numbers = subtractNumbers(universe, chainNumbers(folded[leftStart:left+1]))
+			start = folded[leftStart].Start
+		default:
+			token.Type = TokenUnknown
+			folded = appendUnknown(folded, token)
+			continue
+		}
+
+		folded = folded[:leftStart]
+		expression := Token{Type: TokenNumberSet, Text: text[start:end], Value: text[start:end], Numbers: numbers, Start: start, End: end}
+		if len(numbers) == 0 {
+			expression.Type = TokenUnknown
+			folded = appendUnknown(folded, expression)
+			continue
+		}
+		folded = append(folded, expression)
+	}
+
+	return folded
+}
+
+// foldAdjacentSets 合并直接相连的号码集合：同一分类取并集，不同分类取交集
+func foldAdjacentSets(text string, tokens []Token) []Token {
+	folded := make([]Token, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		j := i
+		for j+1 < len(tokens) && tokens[j].Type == TokenNumberSet && tokens[j+1].Type == TokenNumberSet && tokens[j].End == tokens[j+1].Start {
+			j++
+		}
+		if j == i {
+			folded = append(folded, tokens[i])
+			continue
+		}
+
+		// 同一分类的连续集合先取并集，再与其他分类取交集
+		var numbers []int
+		category := tokens[i].Category
+		for k := i; k <= j; {
+			union := make([]int, 0)
+			run := k
+			for ; run <= j && tokens[run].Category == tokens[k].Category; run++ {
+				union = append(union, tokens[run].Numbers...)
+			}
+			union, _ = dedupeNumbers(union)
+			if numbers == nil {
+				numbers = union
+			} else {
+				numbers = intersectNumbers(numbers, union)
+			}
+			if tokens[k].Category != category {
+				category = ""
+			}
+			k = run
+		}
+
+		start, end := tokens[i].Start, tokens[j].End
+		token := Token{Type: TokenNumberSet, Text: text[start:end], Value: text[start:end], Numbers: numbers, Category: category, Start: start, End: end}
+		if len(numbers) == 0 {
+			token.Type = TokenUnknown
+		}
+		folded = append(folded, token)
+		i = j
+	}
+	return folded
+}
+
+// numericChainStart 从end向前查找由连接符或直接相连组成的号码组的起始位置
+func numericChainStart(tokens []Token, end int) int {
+	start := end
+	for start > 0 {
+		if tokens[start-1].isNumeric() {
+			start--
+		} else if start >= 2 && joinsNumbers(tokens, start-1) {
+			start -= 2
+		} else {
+			break
+		}
+	}
+	return start
+}
+
+// numericChainEnd 从start向后查找由连接符或直接相连组成的号码组的结束位置（含）
+func numericChainEnd(tokens []Token, start int) int {
+	end := start
+	for end+1 < len(tokens) {
+		if tokens[end+1].isNumeric() {
+			end++
+		} else if end+2 < len(tokens) && joinsNumbers(tokens, end+1) {
+			end += 2
+		} else {
+			break
+		}
+	}
+	return end
+}
+
+// chainNumbers 号码组中的所有号码，按出现顺序去重
+func chainNumbers(tokens []Token) []int {
+	numbers := make([]int, 0)
+	for _, token := range tokens {
+		switch token.Type {
+		case TokenNumber:
+			if number, err := strconv.Atoi(token.Value); err == nil {
+				numbers = append(numbers, number)
+			}
+		case TokenNumberSet:
+			numbers = append(numbers, token.Numbers...)
+		}
+	}
+	numbers, _ = dedupeNumbers(numbers)
+	return numbers
+}
+
+// subtractNumbers 返回numbers中不在excluded中的号码，保持原顺序
+func subtractNumbers(numbers []int, excluded []int) []int {
+	result := make([]int, 0, len(numbers))
+	for _, number := range numbers {
+		if !slices.Contains(excluded, number) {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// intersectNumbers 返回numbers中同时在other中的号码，保持原顺序
+func intersectNumbers(numbers []int, other []int) []int {
+	result := make([]int, 0, len(numbers))
+	for _, number := range numbers {
+		if slices.Contains(other, number) {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// isBlankToken 检查是否为空白分隔符
+func isBlankToken(token Token) bool {
+	if token.Type != TokenSeparator {
+		return false
+	}
+	for _, r := range token.Text {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendUnknown 加入无法识别的文字，与前面直接相连的无法识别文字合并
+func appendUnknown(tokens []Token, token Token) []Token {
+	if n := len(tokens); n > 0 && tokens[n-1].Type == TokenUnknown && tokens[n-1].End == token.Start {
+		tokens[n-1].Text += token.Text
+		tokens[n-1].End = token.End
+		return tokens
+	}
+	return append(tokens, token)
+}
@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNumberSetExpressions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		// 排除：去、除、不要
+		{"红波去12", []int{1, 2, 7, 8, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46}},
+		{"红波除1.2", []int{7, 8, 12, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46}},
+		{"红波不要12", []int{1, 2, 7, 8, 13, 18, 19, 23, 24, 29, 30, 34, 35, 40, 45, 46}},
+		// 除外：右侧没有运算对象时从全部号码中排除
+		{"鼠牛除外全场", numbersWhere(func(n int) bool { return !slices.Contains([]int{5, 6, 17, 18, 29, 30, 41, 42}, n) })},
+		{"1.2.3.4除外", numbersWhere(func(n int) bool { return n > 4 })},
+		// 不同分类相连取交集，同一分类相连取并集
+		{"红单", []int{1, 7, 13, 19, 23, 29, 35, 45}},
+		{"绿波双", []int{6, 16, 22, 28, 32, 38, 44}},
+		{"鼠牛", []int{5, 6, 17, 18, 29, 30, 41, 42}},
+	}
+
+	parser := newTestParser()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens := parser.tokenize(tt.input)
+			if len(tokens) != 1 || tokens[0].Type != TokenNumberSet {
+				t.Fatalf("词法单元 = %s, want 一个号码集合", formatTokens(tokens))
+			}
+			got := slices.Clone(tokens[0].Numbers)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("号码 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberSetExpressionDescription(t *testing.T) {
+	result := newTestParser().ParseBetString(BetParseRequest{Input: "二中二 红波去12 各5"})
+	if result.HasError {
+		t.Fatalf("解析失败: %v", result.ErrorMessages)
+	}
+	if result.RoundStatistics.TotalGroups != 120 {
+		t.Errorf("TotalGroups = %d, want 120", result.RoundStatistics.TotalGroups)
+	}
+	// 下注明细中保留原表达式，便于核对
+	for _, lottery := range result.ParsedBets[0].LotteryBets {
+		for _, betType := range lottery.BetTypeDetails {
+			for _, mode := range betType.Modes {
+				for _, detail := range mode.BetDetails {
+					if !strings.Contains(detail.Description, "(红波去12)") {
+						t.Fatalf("Description = %q, 不含表达式", detail.Description)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// defaultPlayerHeaderPattern 默认玩家标识：行首的昵称紧跟冒号，如"张三：三中三1.2.3各5"或单独一行的"张三："
+const defaultPlayerHeaderPattern = `^([^\s:：\d]{1,12})[:：]`
+
+// legacyPlayerHeaderPattern 版本3之前的默认玩家标识，允许行首及冒号前有空白，升级时替换为defaultPlayerHeaderPattern
+const legacyPlayerHeaderPattern = `^\s*([^\s:：\d]{1,12})\s*[:：]`
+
+// playerBlock 按玩家标识拆分出的一段输入
+type playerBlock struct {
+	Player string // 玩家昵称，开头未标明玩家的内容为空
+	Text   string // 该玩家的下注内容
+}
+
+// compilePlayerHeaderPatterns 编译玩家标识表达式，每个表达式须含捕获分组，第一个分组为玩家昵称
+func compilePlayerHeaderPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("玩家标识格式错误: %s: %v", pattern, err)
+		}
+		if re.NumSubexp() < 1 {
+			return nil, fmt.Errorf("玩家标识缺少昵称分组: %s", pattern)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// splitPlayerBlocks 按玩家标识将输入拆分为各玩家的下注内容
+// 没有玩家标识时返回整段输入；同一玩家多次出现时各自成段
+func (p *IntelligentBetParser) splitPlayerBlocks(text string) []playerBlock {
+	blocks := make([]playerBlock, 0)
+	current := playerBlock{}
+	lines := make([]string, 0)
+	flush := func() {
+		current.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+		if current.Player != "" || current.Text != "" {
+			blocks = append(blocks, current)
+		}
+		lines = make([]string, 0)
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if player, rest, ok := p.matchPlayerHeader(line); ok {
+			flush()
+			current = playerBlock{Player: player}
+			line = rest
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return blocks
+}
+
+// matchPlayerHeader 匹配行首的玩家标识，返回玩家昵称及标识之后的内容
+// 昵称为关键词或含关键词时（如"新澳："、"合计："、"共："）不视为玩家标识
+func (p *IntelligentBetParser) matchPlayerHeader(line string) (string, string, bool) {
+	for _, re := range p.playerHeaders {
+		match := re.FindStringSubmatchIndex(line)
+		if match == nil || match[0] != 0 || match[2] < 0 {
+			continue
+		}
+		player := strings.TrimSpace(line[match[2]:match[3]])
+		if player == "" || !p.isPlayerName(player) {
+			continue
+		}
+		return player, line[match[1]:], true
+	}
+	return "", "", false
+}
+
+// isPlayerName 检查文字是否可作为玩家昵称：不是关键词，且不含任何可识别的关键词、号码
+// 声明关键词后没有数值时词法分析视为普通文字（如单独的"合计"、"共"），须按关键词本身排除
+func (p *IntelligentBetParser) isPlayerName(name string) bool {
+	normalized := normalizeText(name)
+	if entry, length := p.trie.longestMatch(normalized); entry != nil && length == len(normalized) {
+		return false
+	}
+	for _, token := range p.tokenize(name) {
+		if token.Type != TokenUnknown && token.Type != TokenSeparator {
+			return false
+		}
+	}
+	return true
+}
+
+// migratePlayerHeaderPatterns 配置文件升级：解析选项中的旧默认玩家标识替换为新的默认玩家标识
+func migratePlayerHeaderPatterns(raw map[string]json.RawMessage) error {
+	data, ok := raw["parser_options"]
+	if !ok {
+		return nil
+	}
+	var options map[string]json.RawMessage
+	if err := json.Unmarshal(data, &options); err != nil || options == nil {
+		// 格式错误的解析选项在加载时报错，这里不做处理
+		return nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(options["player_header_patterns"], &patterns); err != nil {
+		return nil
+	}
+	i := slices.Index(patterns, legacyPlayerHeaderPattern)
+	if i < 0 {
+		return nil
+	}
+	patterns[i] = defaultPlayerHeaderPattern
+
+	var err error
+	if options["player_header_patterns"], err = json.Marshal(patterns); err != nil {
+		return err
+	}
+	raw["parser_options"], err = json.Marshal(options)
+	return err
+}
@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSplitPlayerBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		players []string // 各段的玩家昵称，未标明玩家的段为空
+	}{
+		{"昵称后跟冒号", "张三：三中三1.2.3各5\n李四:二中二5.6各10", []string{"张三", "李四"}},
+		{"单独一行的昵称", "张三：\n三中三1.2.3各5", []string{"张三"}},
+		{"合计声明不是玩家", "三中三1.2.3各5\n合计：5", []string{""}},
+		{"共声明不是玩家", "三中三1.2.3各5\n共：5", []string{""}},
+		{"体彩不是玩家", "新澳：三中三1.2.3各5", []string{""}},
+		{"号码集合不是玩家", "红波：二中二各1", []string{""}},
+		{"冒号前有空白不是玩家", "张三 ：三中三1.2.3各5", []string{""}},
+		{"行首有空白不是玩家", " 张三：三中三1.2.3各5", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := newTestParser().splitPlayerBlocks(tt.input)
+			players := make([]string, len(blocks))
+			for i, block := range blocks {
+				players[i] = block.Player
+			}
+			if !slices.Equal(players, tt.players) {
+				t.Errorf("players = %q, want %q", players, tt.players)
+			}
+		})
+	}
+}
+
+func TestMigratePlayerHeaderPatterns(t *testing.T) {
+	raw := map[string]json.RawMessage{}
+	options, _ := json.Marshal(map[string]interface{}{
+		"strict_unknown_text":    true,
+		"player_header_patterns": []string{legacyPlayerHeaderPattern, `^【(.+)】`},
+	})
+	raw["parser_options"] = options
+
+	if err := migratePlayerHeaderPatterns(raw); err != nil {
+		t.Fatal(err)
+	}
+	var migrated struct {
+		StrictUnknownText    bool     `json:"strict_unknown_text"`
+		PlayerHeaderPatterns []string `json:"player_header_patterns"`
+	}
+	if err := json.Unmarshal(raw["parser_options"], &migrated); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{defaultPlayerHeaderPattern, `^【(.+)】`}
+	if !slices.Equal(migrated.PlayerHeaderPatterns, want) || !migrated.StrictUnknownText {
+		t.Errorf("migrated = %+v, want patterns %q", migrated, want)
+	}
+}
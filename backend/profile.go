@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultProfileName 默认配置方案名称
+const DefaultProfileName = "默认"
+
+// profileNames 所有配置方案概要，当前方案在前
+func profileNames(config *SystemConfig) []ProfileInfo {
+	infos := make([]ProfileInfo, 0, len(config.Profiles)+1)
+	infos = append(infos, ProfileInfo{Name: config.ActiveProfile, Active: true})
+	for _, profile := range config.Profiles {
+		infos = append(infos, ProfileInfo{Name: profile.Name})
+	}
+	return infos
+}
+
+// findProfile 查找未启用的配置方案，不存在时返回-1
+func findProfile(config *SystemConfig, name string) int {
+	for i, profile := range config.Profiles {
+		if profile.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasProfile 配置方案是否存在（含当前方案）
+func hasProfile(config *SystemConfig, name string) bool {
+	return name == config.ActiveProfile || findProfile(config, name) >= 0
+}
+
+// currentProfile 当前方案的号码集合、别名及赔率
+func currentProfile(config *SystemConfig) ConfigProfile {
+	return ConfigProfile{
+		Name:           config.ActiveProfile,
+		NumberSets:     config.NumberSets,
+		BetTypeAliases: config.BetTypeAliases,
+		KeywordAliases: config.KeywordAliases,
+		OddsConfig:     config.OddsConfig,
+	}
+}
+
+// applyProfile 将配置方案的号码集合、别名及赔率设为当前配置
+func applyProfile(config *SystemConfig, profile ConfigProfile) {
+	config.ActiveProfile = profile.Name
+	config.NumberSets = profile.NumberSets
+	config.BetTypeAliases = profile.BetTypeAliases
+	config.KeywordAliases = profile.KeywordAliases
+	config.OddsConfig = profile.OddsConfig
+}
+
+// getProfile 返回配置方案的副本（含当前方案）
+func getProfile(config *SystemConfig, name string) (ConfigProfile, error) {
+	if name == config.ActiveProfile {
+		return cloneProfile(currentProfile(config), name), nil
+	}
+	if i := findProfile(config, name); i >= 0 {
+		return cloneProfile(config.Profiles[i], name), nil
+	}
+	return ConfigProfile{}, fmt.Errorf("配置方案不存在: %s", name)
+}
+
+// cloneProfile 深拷贝配置方案并改名，避免两个方案共用切片
+func cloneProfile(profile ConfigProfile, name string) ConfigProfile {
+	var holder SystemConfig
+	applyProfile(&holder, profile)
+	clone := cloneSystemConfig(&holder)
+	result := currentProfile(&clone)
+	result.Name = name
+	return result
+}
+
+// profileConfig 按配置方案生成配置，name为空或为当前方案时返回config本身
+func profileConfig(config *SystemConfig, name string) (*SystemConfig, error) {
+	if name == "" || name == config.ActiveProfile {
+		return config, nil
+	}
+	profile, err := getProfile(config, name)
+	if err != nil {
+		return nil, err
+	}
+	result := *config
+	applyProfile(&result, profile)
+	return &result, nil
+}
+
+// checkNewProfileName 校验新配置方案的名称
+func checkNewProfileName(config *SystemConfig, name string) error {
+	if name == "" {
+		return fmt.Errorf("配置方案名称不能为空")
+	}
+	if hasProfile(config, name) {
+		return fmt.Errorf("配置方案已存在: %s", name)
+	}
+	return nil
+}
+
+// createProfile 按默认配置新建配置方案
+func createProfile(config *SystemConfig, name string) error {
+	name = strings.TrimSpace(name)
+	if err := checkNewProfileName(config, name); err != nil {
+		return err
+	}
+	defaults := getDefaultSystemConfig()
+	defaults.ActiveProfile = name
+	config.Profiles = append(config.Profiles, currentProfile(defaults))
+	return nil
+}
+
+// copyProfile 复制已有的配置方案
+func copyProfile(config *SystemConfig, source string, name string) error {
+	name = strings.TrimSpace(name)
+	if err := checkNewProfileName(config, name); err != nil {
+		return err
+	}
+	profile, err := getProfile(config, source)
+	if err != nil {
+		return err
+	}
+	profile.Name = name
+	config.Profiles = append(config.Profiles, profile)
+	return nil
+}
+
+// switchProfile 切换当前配置方案：当前方案存入方案列表，目标方案设为当前配置
+func switchProfile(config *SystemConfig, name string) error {
+	if name == config.ActiveProfile {
+		return nil
+	}
+	i := findProfile(config, name)
+	if i < 0 {
+		return fmt.Errorf("配置方案不存在: %s", name)
+	}
+	target := config.Profiles[i]
+	config.Profiles[i] = currentProfile(config)
+	applyProfile(config, target)
+	return nil
+}
+
+// deleteProfile 删除未启用的配置方案
+func deleteProfile(config *SystemConfig, name string) error {
+	if name == config.ActiveProfile {
+		return fmt.Errorf("不能删除当前配置方案，请先切换到其他方案")
+	}
+	i := findProfile(config, name)
+	if i < 0 {
+		return fmt.Errorf("配置方案不存在: %s", name)
+	}
+	config.Profiles = append(config.Profiles[:i:i], config.Profiles[i+1:]...)
+	return nil
+}
@@ -0,0 +1,189 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/shopspring/decimal"
+)
+
+// 回复类型
+const (
+	ReplyAccepted  = "accepted"  // 收单确认
+	ReplyRejected  = "rejected"  // 拒收，附原因
+	ReplySettled   = "settled"   // 结算
+	ReplyStatement = "statement" // 对账单
+)
+
+// 默认回复模板
+// 模板为Go text/template语法，可用字段见replyData，如{{.TotalAmount}}、{{range .Lines}}{{.Lottery}}{{end}}
+const (
+	defaultAcceptedTemplate  = `收到{{range .Lines}} {{.Lottery}} {{.BetType}} {{.Groups}}组×{{.UnitAmount}}={{.Amount}}{{end}} 共{{.TotalAmount}}`
+	defaultRejectedTemplate  = `未能接收: {{join .Errors "；"}}，请修改后重新发送`
+	defaultSettledTemplate   = `{{if .Player}}{{.Player}} {{end}}本期下注{{.TotalAmount}}，中奖{{.Payout}}，盈亏{{.Profit}}`
+	defaultStatementTemplate = `{{if .Player}}{{.Player}} {{end}}对账: 共{{.TotalBets}}笔{{.TotalGroups}}组{{range .Stats}}
+{{.Lottery}} {{.BetType}} {{.Groups}}组 {{.Amount}}{{end}}
+合计{{.TotalAmount}}，中奖{{.Payout}}，盈亏{{.Profit}}`
+)
+
+// 下注模式在回复中的名称
+var replyModeNames = map[string]string{
+	"multiple": "单式",
+	"complex":  "复式",
+	"drag":     "拖码",
+}
+
+// replyLine 回复中的一行下注明细：一个体彩的一种下注类型的一种模式
+type replyLine struct {
+	Lottery    string          // 体彩
+	BetType    string          // 下注类型
+	Mode       string          // 模式（单式、复式、拖码）
+	Groups     int             // 组数
+	UnitAmount decimal.Decimal // 单组金额
+	Amount     decimal.Decimal // 金额
+	Text       string          // 原始下注文本
+}
+
+// replyStat 回复中按体彩、下注类型汇总的统计
+type replyStat struct {
+	Lottery string          // 体彩
+	BetType string          // 下注类型
+	Groups  int             // 组数
+	Count   int             // 笔数
+	Amount  decimal.Decimal // 金额
+}
+
+// replyData 回复模板可用的数据
+type replyData struct {
+	Player      string          // 玩家昵称
+	Lines       []replyLine     // 下注明细（不含错误的下注）
+	Stats       []replyStat     // 按体彩、下注类型汇总
+	TotalAmount decimal.Decimal // 总金额
+	TotalGroups int             // 总组数
+	TotalBets   int             // 总笔数
+	Errors      []string        // 错误信息
+	Warnings    []string        // 提示信息
+	Payout      decimal.Decimal // 中奖金额（结算、对账单）
+	Profit      decimal.Decimal // 玩家盈亏：中奖金额减下注金额
+}
+
+// replyTemplateFuncs 回复模板可用的函数
+var replyTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// textFor 返回指定类型的模板文本，未配置时使用默认模板
+func (t ReplyTemplates) textFor(kind string) (string, error) {
+	var text, fallback string
+	switch kind {
+	case ReplyAccepted:
+		text, fallback = t.Accepted, defaultAcceptedTemplate
+	case ReplyRejected:
+		text, fallback = t.Rejected, defaultRejectedTemplate
+	case ReplySettled:
+		text, fallback = t.Settled, defaultSettledTemplate
+	case ReplyStatement:
+		text, fallback = t.Statement, defaultStatementTemplate
+	default:
+		return "", fmt.Errorf("未知的回复类型: %s", kind)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fallback, nil
+	}
+	return text, nil
+}
+
+// validate 检查所有模板的语法
+func (t ReplyTemplates) validate() error {
+	for _, kind := range []string{ReplyAccepted, ReplyRejected, ReplySettled, ReplyStatement} {
+		text, _ := t.textFor(kind)
+		if _, err := template.New(kind).Funcs(replyTemplateFuncs).Parse(text); err != nil {
+			return fmt.Errorf("回复模板格式错误(%s): %v", kind, err)
+		}
+	}
+	return nil
+}
+
+// renderReply 按模板生成一轮下注的回复文本
+// kind为空时按解析结果选择收单确认或拒收
+func renderReply(templates ReplyTemplates, kind string, result BetParsingResult, payout decimal.Decimal) (string, error) {
+	if kind == "" {
+		kind = ReplyAccepted
+		if result.HasError {
+			kind = ReplyRejected
+		}
+	}
+	text, err := templates.textFor(kind)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(kind).Funcs(replyTemplateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("回复模板格式错误(%s): %v", kind, err)
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, newReplyData(result, payout)); err != nil {
+		return "", fmt.Errorf("生成回复失败: %v", err)
+	}
+	return builder.String(), nil
+}
+
+// newReplyData 由解析结果整理回复模板数据
+func newReplyData(result BetParsingResult, payout decimal.Decimal) replyData {
+	data := replyData{
+		Player:      result.Player,
+		Lines:       make([]replyLine, 0),
+		Stats:       make([]replyStat, 0),
+		TotalAmount: result.RoundStatistics.TotalAmount,
+		TotalGroups: result.RoundStatistics.TotalGroups,
+		TotalBets:   result.RoundStatistics.TotalBets,
+		Errors:      result.ErrorMessages,
+		Warnings:    result.WarningMessages,
+		Payout:      payout,
+		Profit:      payout.Sub(result.RoundStatistics.TotalAmount),
+	}
+
+	for _, bet := range result.ParsedBets {
+		if bet.HasError {
+			continue
+		}
+		for _, lottery := range sortLotteries(sortedKeys(bet.LotteryBets)) {
+			details := bet.LotteryBets[lottery].BetTypeDetails
+			for _, betType := range betTypeOrder {
+				detail, ok := details[betType]
+				if !ok {
+					continue
+				}
+				for _, modeName := range sortedKeys(detail.Modes) {
+					mode := detail.Modes[modeName]
+					unitAmount := mode.UnitAmount
+					if unitAmount.IsZero() && mode.Groups > 0 {
+						unitAmount = mode.Amount.Div(decimal.NewFromInt(int64(mode.Groups)))
+					}
+					data.Lines = append(data.Lines, replyLine{
+						Lottery:    lottery,
+						BetType:    betType,
+						Mode:       replyModeNames[modeName],
+						Groups:     mode.Groups,
+						UnitAmount: unitAmount,
+						Amount:     mode.Amount,
+						Text:       bet.OriginalText,
+					})
+				}
+			}
+		}
+	}
+
+	for _, lottery := range sortLotteries(sortedKeys(result.RoundStatistics.LotteryBetTypeStats)) {
+		stats := result.RoundStatistics.LotteryBetTypeStats[lottery]
+		for _, betType := range betTypeOrder {
+			if stat, ok := stats[betType]; ok {
+				data.Stats = append(data.Stats, replyStat{Lottery: lottery, BetType: betType, Groups: stat.Groups, Count: stat.Count, Amount: stat.Amount})
+			}
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRenderReply(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		kind      string
+		templates ReplyTemplates
+		want      string
+	}{
+		{
+			name:  "收单确认",
+			input: "新澳 三中三1.2.3.4各5\n老澳 二中二5.6各20",
+			want:  "收到 新澳 三中三 4组×5=20 老澳 二中二 1组×20=20 共40",
+		},
+		{
+			name:  "解析错误时拒收",
+			input: "三中三1.2各5",
+			want:  "未能接收: 三中三没有有效的下注号码，三中三每组需要3个号码，请修改后重新发送",
+		},
+		{
+			name:  "结算",
+			input: "新澳 三中三1.2.3.4各5",
+			kind:  ReplySettled,
+			want:  "本期下注20，中奖100，盈亏80",
+		},
+		{
+			name:  "对账单",
+			input: "新澳 三中三1.2.3.4各5\n老澳 二中二5.6各20",
+			kind:  ReplyStatement,
+			want:  "对账: 共2笔5组\n新澳 三中三 4组 20\n老澳 二中二 1组 20\n合计40，中奖100，盈亏60",
+		},
+		{
+			name:      "自定义模板",
+			input:     "新澳 三中三1.2.3.4各5",
+			templates: ReplyTemplates{Accepted: "{{range .Lines}}{{.Lottery}}{{.Mode}}{{.BetType}}{{end}} {{.TotalGroups}}组 {{.TotalAmount}}元"},
+			want:      "新澳复式三中三 4组 20元",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestParser().ParseBetString(BetParseRequest{Input: tt.input})
+			got, err := renderReply(tt.templates, tt.kind, result, decimal.NewFromInt(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("renderReply = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplyTemplatesValidate(t *testing.T) {
+	if err := (ReplyTemplates{}).validate(); err != nil {
+		t.Errorf("默认模板校验失败: %v", err)
+	}
+	if err := (ReplyTemplates{Settled: "{{.TotalAmount"}).validate(); err == nil {
+		t.Error("模板语法错误应校验失败")
+	}
+	if _, err := renderReply(ReplyTemplates{}, "unknown", BetParsingResult{}, decimal.Zero); err == nil {
+		t.Error("未知的回复类型应返回错误")
+	}
+}
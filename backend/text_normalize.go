@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"strings"
+)
+
+// traditionalToSimplified 繁体字到简体字的映射，覆盖默认配置中所有关键词、别名及号码集合名称中有繁体写法的字，
+// 以及配置别名、声明中常用的字；新增默认关键词时须同步补充（见text_normalize_test.go）
+// 映射为逐字替换，替换前后字数不变
+var traditionalToSimplified = map[rune]rune{
+	// 生肖及家禽野兽
+	'龍': '龙', '馬': '马', '雞': '鸡', '鷄': '鸡', '豬': '猪', '獸': '兽',
+	// 波色
+	'紅': '红', '藍': '蓝', '綠': '绿',
+	// 单双、头数、全场
+	'單': '单', '雙': '双', '頭': '头', '場': '场', '塲': '场',
+	// 下注类型
+	'種': '种',
+	// 体彩
+	'門': '门', '舊': '旧',
+	// 模式
+	'復': '复', '複': '复', '試': '试', '組': '组', '碼': '码', '號': '号',
+	// 金额关键词
+	'個': '个', '別': '别', '塊': '块', '圓': '元',
+	// 声明关键词
+	'數': '数', '計': '计', '總': '总', '縂': '总',
+	// 中文数字
+	'兩': '两', '萬': '万',
+	// 常用于别名的字
+	'連': '连', '錢': '钱', '買': '买', '壓': '压', '註': '注',
+	// 回复模板及结算用语
+	'發': '发', '後': '后', '對': '对', '筆': '笔', '獎': '奖', '奬': '奖', '虧': '亏', '贏': '赢', '賠': '赔', '認': '认', '請': '请', '賬': '账',
+}
+
+// normalizeRune 规范化单个字符：全角转半角、繁体转简体
+func normalizeRune(r rune) rune {
+	switch {
+	case r >= '！' && r <= '～':
+		// 全角ASCII字符（U+FF01~U+FF5E）与半角字符一一对应
+		return r - '！' + '!'
+	case r == '　':
+		return ' '
+	case r == '。' || r == '｡':
+		return '.'
+	case r == '–' || r == '—':
+		// 短破折号、长破折号常被输入法当作连字符，如"5–13–32"
+		return '-'
+	}
+	if simplified, ok := traditionalToSimplified[r]; ok {
+		return simplified
+	}
+	return r
+}
+
+// normalizeText 规范化下注文本，在词法分析之前进行
+// 逐字替换，规范化后的字数与原文相同，但全角转半角、繁体转简体会改变字节长度
+// CJK扩展区的汉字无需转换，词法分析按unicode.IsLetter统一视为文字
+func normalizeText(text string) string {
+	return strings.Map(normalizeRune, text)
+}
+
+// normalizeTextWithOffsets 规范化文本，同时返回位置映射：offsets[i]为规范化文本中字节位置i在原文中的字节位置
+// 词法单元的位置按该映射换算为原文中的位置
+func normalizeTextWithOffsets(text string) (string, []int) {
+	var builder strings.Builder
+	builder.Grow(len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		start := builder.Len()
+		builder.WriteRune(normalizeRune(r))
+		for j := start; j < builder.Len(); j++ {
+			offsets = append(offsets, i)
+		}
+	}
+	offsets = append(offsets, len(text))
+	return builder.String(), offsets
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// noTraditionalForm 默认关键词中繁简写法相同的字
+const noTraditionalForm = "一三二四五六七八九十百千零中下不去除掉要外共合各每分都" +
+	"鼠牛虎兔蛇羊猴狗家禽畜野肖波色大小全尾拖式死活特碰新老澳港香金木水火土"
+
+// defaultKeywords 默认配置中的所有关键词、别名及号码集合名称
+func defaultKeywords(config IntelligentBetParserConfig) []string {
+	keywords := make([]string, 0)
+	for _, aliases := range []map[string][]string{config.BetTypeAliases, config.LotteryAliases, config.KeywordAliases, config.EndKeywords} {
+		for name, list := range aliases {
+			keywords = append(keywords, name)
+			keywords = append(keywords, list...)
+		}
+	}
+	for _, set := range config.NumberSets {
+		keywords = append(keywords, set.Name)
+		keywords = append(keywords, set.Aliases...)
+	}
+	for keyword := range declarationKeywords {
+		keywords = append(keywords, keyword)
+	}
+	for keyword := range setOperatorKeywords {
+		keywords = append(keywords, keyword)
+	}
+	return append(keywords, strings.Split(chineseNumerals, "")...)
+}
+
+// toTraditional 按繁简映射将关键词改写为繁体
+func toTraditional(text string) string {
+	traditional := make(map[rune]rune, len(traditionalToSimplified))
+	for from, to := range traditionalToSimplified {
+		if existing, ok := traditional[to]; !ok || from < existing {
+			traditional[to] = from
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if from, ok := traditional[r]; ok {
+			return from
+		}
+		return r
+	}, text)
+}
+
+func TestTraditionalToSimplifiedCoversDefaultKeywords(t *testing.T) {
+	parser := newTestParser()
+	simplified := make(map[rune]bool, len(traditionalToSimplified))
+	for _, to := range traditionalToSimplified {
+		simplified[to] = true
+	}
+
+	for _, keyword := range defaultKeywords(parser.config) {
+		for _, r := range keyword {
+			if unicode.Is(unicode.Han, r) && !simplified[r] && !strings.ContainsRune(noTraditionalForm, r) {
+				t.Errorf("关键词%q中的%q没有繁体映射，也不在noTraditionalForm中", keyword, string(r))
+			}
+		}
+
+		want := parser.tokenize(keyword)
+		got := parser.tokenize(toTraditional(keyword))
+		if len(got) != len(want) {
+			t.Errorf("繁体关键词%q的词法单元为%s，want %s", toTraditional(keyword), formatTokens(got), formatTokens(want))
+			continue
+		}
+		for i := range want {
+			if got[i].Type != want[i].Type || got[i].Value != want[i].Value {
+				t.Errorf("繁体关键词%q的词法单元为%s，want %s", toTraditional(keyword), formatTokens(got), formatTokens(want))
+				break
+			}
+		}
+	}
+}
+
+func TestParseBetStringTraditional(t *testing.T) {
+	tests := []struct {
+		traditional string
+		simplified  string
+	}{
+		{"紅單三中三各1", "红单三中三各1"},
+		{"雙紅三中三各1", "双红三中三各1"},
+		{"龍 1頭 三中三各1", "龙 1头 三中三各1"},
+		{"二中二 野獸 各1", "二中二 野兽 各1"},
+		{"二中二 鼠牛除外全場 各1", "二中二 鼠牛除外全场 各1"},
+		{"舊澳門 複試三中三 1.2.3.4 各5 共計20", "旧澳门 复试三中三 1.2.3.4 各5 共计20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.traditional, func(t *testing.T) {
+			got := newTestParser().ParseBetString(BetParseRequest{Input: tt.traditional})
+			want := newTestParser().ParseBetString(BetParseRequest{Input: tt.simplified})
+			if want.HasError || want.RoundStatistics.TotalGroups == 0 {
+				t.Fatalf("简体%q解析失败: %v", tt.simplified, want.ErrorMessages)
+			}
+			if got.HasError {
+				t.Fatalf("解析失败: %v", got.ErrorMessages)
+			}
+			if got.RoundStatistics.TotalGroups != want.RoundStatistics.TotalGroups ||
+				!got.RoundStatistics.TotalAmount.Equal(want.RoundStatistics.TotalAmount) {
+				t.Errorf("解析为%d组%s元，want %d组%s元", got.RoundStatistics.TotalGroups, got.RoundStatistics.TotalAmount,
+					want.RoundStatistics.TotalGroups, want.RoundStatistics.TotalAmount)
+			}
+			if got.RoundStatistics.TotalAmount.Equal(decimal.Zero) {
+				t.Errorf("总金额为0")
+			}
+		})
+	}
+}
+
+func TestTokenSpansInOriginalText(t *testing.T) {
+	parser := newTestParser()
+	for _, input := range []string{
+		"三中三１．２．３各５",
+		"舊澳門 ｛龍｝１０／２０各１０",
+		"二中二 紅單去１ 各５",
+	} {
+		tokens := parser.tokenize(input)
+		end := 0
+		for _, token := range tokens {
+			if token.Start != end || token.End > len(input) {
+				t.Fatalf("%q的词法单元位置不连续: %s", input, formatTokens(tokens))
+			}
+			if got := normalizeText(input[token.Start:token.End]); got != token.Text {
+				t.Errorf("%q中%s[%d:%d]对应原文%q，规范化为%q，want %q", input, token.Type, token.Start, token.End,
+					input[token.Start:token.End], got, token.Text)
+			}
+			end = token.End
+		}
+		if end != len(input) {
+			t.Errorf("%q的词法单元在%d处结束，want %d", input, end, len(input))
+		}
+	}
+}
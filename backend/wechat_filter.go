@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// 引用回复：「张三：原消息」，可跨行
+	quotedReplyRe = regexp.MustCompile(`「[^「」]*」`)
+	// 引用回复下方的分隔线：- - - - - -
+	quoteSeparatorRe = regexp.MustCompile(`(?m)^[ \t]*(?:-[ \t]*){3,}$`)
+	// @提及：@昵称后必须跟空白（微信使用U+2005），保留其后的空白
+	// 昵称后直接跟下注内容（如"@张三三中三1.2.3各5"）时无法区分昵称的结束位置，不做处理
+	mentionRe = regexp.MustCompile(`@[^\s\x{2005}@]{1,20}([\s\x{2005}])`)
+	// 表情代码：[强]、[玫瑰]、[Smile]
+	emojiCodeRe = regexp.MustCompile(`\[[\p{Han}A-Za-z]{1,8}\]`)
+	// 撤回消息提示："张三"撤回了一条消息、你撤回了一条消息 重新编辑
+	recallNoticeRe = regexp.MustCompile(`(?m)^.*撤回了一条消息.*$`)
+	// 发送者及时间：张三 2026-10-17 20:31:05，分组依次为昵称、日期、时间
+	senderHeaderRe = regexp.MustCompile(`(?m)^([^\n]{1,30}?)[ \t]+(\d{4}[-/年]\d{1,2}[-/月]\d{1,2}日?)[ \t]+(\d{1,2}:\d{2}(?::\d{2})?)[ \t]*$`)
+)
+
+// stripWeChatNoise 去除粘贴的微信消息中的引用回复、@提及、表情代码等内容，在词法分析之前进行
+// 返回过滤后的文本及被去除的内容
+func (p *IntelligentBetParser) stripWeChatNoise(text string) (string, []string) {
+	options := p.config.WeChatFilter
+	removed := make([]string, 0)
+
+	strip := func(enabled bool, re *regexp.Regexp, replace func(match string) string) {
+		if !enabled {
+			return
+		}
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			replacement := replace(match)
+			if trimmed := strings.TrimSpace(match); trimmed != "" && replacement != match {
+				removed = append(removed, trimmed)
+			}
+			return replacement
+		})
+	}
+	// 整行内容替换为空行，行内内容替换为空格，避免前后文字粘连
+	blankLine := func(string) string { return "" }
+
+	strip(options.RecallNotices, recallNoticeRe, blankLine)
+	strip(options.SenderHeaders, senderHeaderRe, blankLine)
+	strip(options.QuotedReplies, quotedReplyRe, func(string) string { return "\n" })
+	strip(options.QuotedReplies, quoteSeparatorRe, blankLine)
+	strip(options.Mentions, mentionRe, func(match string) string {
+		return mentionRe.ReplaceAllString(match, "$1")
+	})
+	strip(options.EmojiCodes, emojiCodeRe, func(match string) string {
+		// 方括号内为关键词时（如"[三中三]"）保留
+		inner := normalizeText(strings.Trim(match, "[]"))
+		if entry, length := p.trie.longestMatch(inner); entry != nil && length == len(inner) {
+			return match
+		}
+		return " "
+	})
+
+	return text, removed
+}
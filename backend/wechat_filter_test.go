@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStripWeChatNoiseMentions(t *testing.T) {
+	parser := newTestParser()
+	tests := []struct {
+		name        string
+		input       string
+		wantText    string
+		wantRemoved []string
+	}{
+		{"微信分隔符", "@张三\u2005三中三1.2.3各5", "\u2005三中三1.2.3各5", []string{"@张三"}},
+		{"空格", "@张三 三中三1.2.3各5", " 三中三1.2.3各5", []string{"@张三"}},
+		{"独占一行", "@张三\n三中三1.2.3各5", "\n三中三1.2.3各5", []string{"@张三"}},
+		{"昵称后直接跟下注内容", "@张三三中三1.2.3各5", "@张三三中三1.2.3各5", []string{}},
+		{"行尾", "三中三1.2.3各5 @张三", "三中三1.2.3各5 @张三", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, removed := parser.stripWeChatNoise(tt.input)
+			if text != tt.wantText {
+				t.Errorf("stripWeChatNoise(%q) = %q, want %q", tt.input, text, tt.wantText)
+			}
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("stripWeChatNoise(%q) removed = %q, want %q", tt.input, removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestStripWeChatNoise(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantRemoved []string
+		amount      string // 过滤后解析的总金额
+	}{
+		{
+			name:        "引用回复及分隔线",
+			input:       "「张三：三中三1.2.3各5」\n- - - - - - - - - - - - - - -\n二中二4.5各10",
+			wantRemoved: []string{"「张三：三中三1.2.3各5」", "- - - - - - - - - - - - - - -"},
+			amount:      "10",
+		},
+		{
+			name:        "表情代码",
+			input:       "三中三1.2.3各5[强][玫瑰]",
+			wantRemoved: []string{"[强]", "[玫瑰]"},
+			amount:      "5",
+		},
+		{
+			name:        "方括号内为关键词时保留",
+			input:       "[三中三]1.2.3各5",
+			wantRemoved: []string{},
+			amount:      "5",
+		},
+		{
+			name:        "撤回消息提示",
+			input:       "\"张三\"撤回了一条消息\n二中二4.5各10",
+			wantRemoved: []string{"\"张三\"撤回了一条消息"},
+			amount:      "10",
+		},
+		{
+			name:        "发送者及时间",
+			input:       "张三 2026-10-17 20:31:05\n二中二4.5各10",
+			wantRemoved: []string{"张三 2026-10-17 20:31:05"},
+			amount:      "10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := newTestParser()
+			if _, removed := parser.stripWeChatNoise(tt.input); !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("removed = %q, want %q", removed, tt.wantRemoved)
+			}
+			result := parser.ParseBetString(BetParseRequest{Input: tt.input})
+			if result.HasError {
+				t.Fatalf("解析失败: %v", result.ErrorMessages)
+			}
+			if !result.RoundStatistics.TotalAmount.Equal(decimal.RequireFromString(tt.amount)) {
+				t.Errorf("TotalAmount = %s, want %s", result.RoundStatistics.TotalAmount, tt.amount)
+			}
+		})
+	}
+
+	// 关闭的过滤项不去除
+	parser := newTestParser()
+	parser.config.WeChatFilter = WeChatFilterOptions{}
+	input := "三中三1.2.3各5[强]"
+	if text, removed := parser.stripWeChatNoise(input); text != input || len(removed) != 0 {
+		t.Errorf("关闭过滤后stripWeChatNoise(%q) = %q, %q", input, text, removed)
+	}
+}
@@ -0,0 +1,213 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// defaultLunarNewYears 默认农历新年（正月初一）日期，key: 公历年份
+// 新年日期不按立春等规则推算，未配置的年份不能自动换肖，延长时须同步升级配置文件（见migrateLunarNewYears）
+var defaultLunarNewYears = map[string]string{
+	"2020": "2020-01-25",
+	"2021": "2021-02-12",
+	"2022": "2022-02-01",
+	"2023": "2023-01-22",
+	"2024": "2024-02-10",
+	"2025": "2025-01-29",
+	"2026": "2026-02-17",
+	"2027": "2027-02-06",
+	"2028": "2028-01-26",
+	"2029": "2029-02-13",
+	"2030": "2030-02-03",
+	"2031": "2031-01-23",
+	"2032": "2032-02-11",
+	"2033": "2033-01-31",
+	"2034": "2034-02-19",
+	"2035": "2035-02-08",
+	"2036": "2036-01-28",
+	"2037": "2037-02-15",
+	"2038": "2038-02-04",
+	"2039": "2039-01-24",
+	"2040": "2040-02-12",
+	"2041": "2041-02-01",
+	"2042": "2042-01-22",
+	"2043": "2043-02-10",
+	"2044": "2044-01-30",
+	"2045": "2045-02-17",
+	"2046": "2046-02-06",
+	"2047": "2047-01-26",
+	"2048": "2048-02-14",
+	"2049": "2049-02-02",
+	"2050": "2050-01-23",
+	"2051": "2051-02-11",
+	"2052": "2052-02-01",
+	"2053": "2053-02-19",
+	"2054": "2054-02-08",
+	"2055": "2055-01-28",
+	"2056": "2056-02-15",
+	"2057": "2057-02-04",
+	"2058": "2058-01-24",
+	"2059": "2059-02-12",
+	"2060": "2060-02-02",
+}
+
+// 家禽、野兽包含的生肖，换肖时随生肖号码一起更新
+var animalZodiacs = map[string][]string{
+	"家禽": {"牛", "马", "羊", "鸡", "狗", "猪"},
+	"野兽": {"鼠", "虎", "兔", "龙", "蛇", "猴"},
+}
+
+// 五行的顺序
+var elementNames = []string{"金", "木", "水", "火", "土"}
+
+// nayinElements 六十甲子纳音五行，每两个干支同一纳音，从甲子、乙丑（海中金）开始
+var nayinElements = []string{
+	"金", "火", "木", "土", "金", "火", "水", "土", "金", "木",
+	"水", "土", "火", "木", "水", "金", "火", "木", "土", "金",
+	"火", "水", "土", "金", "木", "水", "土", "火", "木", "水",
+}
+
+// elementNumbersForYear 生成农历年份的五行号码表：号码n对应往前数第n年（号码1为当年）的干支，取其纳音五行
+// 如2025年（乙巳，覆灯火）：火01-02-09-10-17-18-31-32-39-40-47-48，金03-04-11-12-25-26-33-34-41-42……
+func elementNumbersForYear(year int) map[string][]int {
+	table := make(map[string][]int, len(elementNames))
+	for n := MinLotteryNumber; n <= MaxLotteryNumber; n++ {
+		// 1984年为甲子年
+		index := ((year-(n-1)-1984)%60 + 60) % 60
+		name := nayinElements[index/2]
+		table[name] = append(table[name], n)
+	}
+	return table
+}
+
+// zodiacOfYear 农历年份的生肖，如2024为龙
+func zodiacOfYear(year int) string {
+	return zodiacNames[((year-4)%12+12)%12]
+}
+
+// zodiacNumbersForYear 生成农历年份的生肖号码表：本命生肖为1、13、25、37、49，之后的号码按生肖顺序倒推
+// 如龙年：龙01-13-25-37-49，兔02-14-26-38，虎03-15-27-39……
+func zodiacNumbersForYear(year int) map[string][]int {
+	yearIndex := slices.Index(zodiacNames, zodiacOfYear(year))
+	table := make(map[string][]int, len(zodiacNames))
+	for n := MinLotteryNumber; n <= MaxLotteryNumber; n++ {
+		name := zodiacNames[((yearIndex-(n-1))%12+12)%12]
+		table[name] = append(table[name], n)
+	}
+	return table
+}
+
+// newYearDate 公历年份中农历新年的日期，未配置该年份时返回错误
+func newYearDate(year int, dates map[string]string) (time.Time, error) {
+	text, ok := dates[strconv.Itoa(year)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("未配置%d年的农历新年日期", year)
+	}
+	date, err := time.ParseInLocation("2006-01-02", text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%d年的新年日期格式错误: %s", year, text)
+	}
+	return date, nil
+}
+
+// lunarYearAt 指定时间所在的农历年份：新年日期之前仍属上一年
+// 新年日期未配置或格式错误时记录日志，按公历年份换肖；配置校验保证当年及下一年的新年日期已配置
+func lunarYearAt(t time.Time, dates map[string]string) int {
+	year := t.Year()
+	date, err := newYearDate(year, dates)
+	if err != nil {
+		safeLogger.AppendLog(fmt.Sprintf("%v，按公历年份换肖", err))
+		return year
+	}
+	if t.Before(date) {
+		return year - 1
+	}
+	return year
+}
+
+// zodiacTableForYear 农历年份的生肖号码表
+func zodiacTableForYear(year int) ZodiacTable {
+	return ZodiacTable{
+		LunarYear:  year,
+		YearZodiac: zodiacOfYear(year),
+		Numbers:    zodiacNumbersForYear(year),
+	}
+}
+
+// zodiacTableFromNumberSets 由号码集合中的生肖整理号码表，用于关闭自动换肖时记录实际使用的号码表
+func zodiacTableFromNumberSets(sets []NumberSet) ZodiacTable {
+	table := ZodiacTable{Numbers: make(map[string][]int)}
+	for _, set := range numberSetsByCategory(sets, NumberSetZodiac) {
+		table.Numbers[set.Name] = set.Numbers
+		if slices.Contains(set.Numbers, 1) {
+			table.YearZodiac = set.Name
+		}
+	}
+	return table
+}
+
+// applyZodiacTable 返回按生肖号码表更新后的号码集合副本：生肖及由生肖组成的家禽、野兽
+// 号码表有农历年份时，五行也按该年份更新
+func applyZodiacTable(sets []NumberSet, table ZodiacTable) []NumberSet {
+	result := make([]NumberSet, len(sets))
+	copy(result, sets)
+	var elementNumbers map[string][]int
+	if table.LunarYear > 0 {
+		elementNumbers = elementNumbersForYear(table.LunarYear)
+	}
+	for i, set := range result {
+		switch {
+		case set.Category == NumberSetZodiac:
+			if numbers, ok := table.Numbers[set.Name]; ok {
+				result[i].Numbers = numbers
+			}
+		case set.Category == NumberSetAnimal && animalZodiacs[set.Name] != nil:
+			numbers := make([]int, 0)
+			for _, name := range animalZodiacs[set.Name] {
+				numbers = append(numbers, table.Numbers[name]...)
+			}
+			slices.Sort(numbers)
+			result[i].Numbers = numbers
+		case set.Category == NumberSetElement && elementNumbers != nil:
+			if numbers, ok := elementNumbers[set.Name]; ok {
+				result[i].Numbers = numbers
+			}
+		}
+	}
+	return result
+}
+
+// migrateLunarNewYears 配置文件升级：换肖设置中补充默认新年日期中新增的年份，已配置的年份不做修改
+func migrateLunarNewYears(raw map[string]json.RawMessage) error {
+	data, ok := raw["zodiac_schedule"]
+	if !ok {
+		return nil
+	}
+	var schedule map[string]json.RawMessage
+	if err := json.Unmarshal(data, &schedule); err != nil || schedule == nil {
+		// 格式错误的换肖设置在加载时报错，这里不做处理
+		return nil
+	}
+	var dates map[string]string
+	if err := json.Unmarshal(schedule["new_year_dates"], &dates); err != nil {
+		return nil
+	}
+	if dates == nil {
+		dates = make(map[string]string)
+	}
+	for year, date := range defaultLunarNewYears {
+		if _, ok := dates[year]; !ok {
+			dates[year] = date
+		}
+	}
+
+	var err error
+	if schedule["new_year_dates"], err = json.Marshal(dates); err != nil {
+		return err
+	}
+	raw["zodiac_schedule"], err = json.Marshal(schedule)
+	return err
+}
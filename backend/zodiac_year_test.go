@@ -0,0 +1,123 @@
+package backend
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestElementNumbersForYear(t *testing.T) {
+	want := map[string][]int{
+		"金": {3, 4, 11, 12, 25, 26, 33, 34, 41, 42},
+		"木": {7, 8, 15, 16, 23, 24, 37, 38, 45, 46},
+		"水": {13, 14, 21, 22, 29, 30, 43, 44},
+		"火": {1, 2, 9, 10, 17, 18, 31, 32, 39, 40, 47, 48},
+		"土": {5, 6, 19, 20, 27, 28, 35, 36, 49},
+	}
+	got := elementNumbersForYear(2025)
+	for _, name := range elementNames {
+		if !slices.Equal(got[name], want[name]) {
+			t.Errorf("2025年%s = %v, want %v", name, got[name], want[name])
+		}
+	}
+
+	seen := make(map[int]bool)
+	for _, numbers := range elementNumbersForYear(2026) {
+		for _, n := range numbers {
+			if seen[n] {
+				t.Errorf("号码%d属于多个五行", n)
+			}
+			seen[n] = true
+		}
+	}
+	if len(seen) != MaxLotteryNumber {
+		t.Errorf("五行覆盖%d个号码, want %d", len(seen), MaxLotteryNumber)
+	}
+}
+
+func TestApplyZodiacTableUpdatesElements(t *testing.T) {
+	sets := elementNumberSets(2024)
+	updated := applyZodiacTable(sets, zodiacTableForYear(2025))
+	for i, set := range updated {
+		if !slices.Equal(set.Numbers, elementNumbersForYear(2025)[set.Name]) {
+			t.Errorf("%s = %v, 未按2025年更新", set.Name, set.Numbers)
+		}
+		if slices.Equal(sets[i].Numbers, set.Numbers) {
+			t.Errorf("%s 2024与2025年号码相同", set.Name)
+		}
+	}
+}
+
+func TestMigrateElementNumberSets(t *testing.T) {
+	raw := map[string]json.RawMessage{
+		"number_sets": json.RawMessage(`[{"name":"红波","category":"波色","numbers":[1,2]}]`),
+		"profiles":    json.RawMessage(`[{"name":"A","number_sets":[{"name":"金","category":"自定义","numbers":[1]}]}]`),
+	}
+	if err := migrateElementNumberSets(raw); err != nil {
+		t.Fatal(err)
+	}
+	var sets []NumberSet
+	if err := json.Unmarshal(raw["number_sets"], &sets); err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 1+len(elementNames) || sets[1].Category != NumberSetElement {
+		t.Errorf("号码集合未加入五行: %+v", sets)
+	}
+
+	var profiles []struct {
+		NumberSets []NumberSet `json:"number_sets"`
+	}
+	if err := json.Unmarshal(raw["profiles"], &profiles); err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles[0].NumberSets) != 1 {
+		t.Errorf("已有同名集合的配置方案不应修改: %+v", profiles[0].NumberSets)
+	}
+}
+
+func TestNewYearDateRequiresConfiguredYear(t *testing.T) {
+	date, err := newYearDate(2036, defaultLunarNewYears)
+	if err != nil || date.Format("2006-01-02") != "2036-01-28" {
+		t.Errorf("newYearDate(2036) = %v, %v, want 2036-01-28", date, err)
+	}
+	if _, err := newYearDate(2099, defaultLunarNewYears); err == nil {
+		t.Error("未配置的年份应返回错误")
+	}
+
+	// 自动换肖时缺少当年或下一年的新年日期为配置错误
+	year := time.Now().Year()
+	dates := map[string]string{strconv.Itoa(year): defaultLunarNewYears[strconv.Itoa(year)]}
+	if err := validateZodiacSchedule(ZodiacSchedule{AutoUpdate: true, NewYearDates: dates}); err == nil {
+		t.Error("缺少下一年的新年日期应校验失败")
+	}
+	if err := validateZodiacSchedule(ZodiacSchedule{AutoUpdate: false, NewYearDates: dates}); err != nil {
+		t.Errorf("关闭自动换肖时不要求新年日期: %v", err)
+	}
+	if err := validateZodiacSchedule(getDefaultSystemConfig().ZodiacSchedule); err != nil {
+		t.Errorf("默认换肖设置校验失败: %v", err)
+	}
+}
+
+func TestMigrateLunarNewYears(t *testing.T) {
+	raw := map[string]json.RawMessage{
+		"zodiac_schedule": json.RawMessage(`{"auto_update":true,"new_year_dates":{"2035":"2035-02-09"}}`),
+	}
+	if err := migrateLunarNewYears(raw); err != nil {
+		t.Fatal(err)
+	}
+	var schedule ZodiacSchedule
+	if err := json.Unmarshal(raw["zodiac_schedule"], &schedule); err != nil {
+		t.Fatal(err)
+	}
+	if !schedule.AutoUpdate {
+		t.Error("升级后自动换肖被关闭")
+	}
+	if got := schedule.NewYearDates["2035"]; got != "2035-02-09" {
+		t.Errorf("已配置的2035年被修改为%s", got)
+	}
+	if got := schedule.NewYearDates["2036"]; got != "2036-01-28" {
+		t.Errorf("2036年新年日期 = %q, want 2036-01-28", got)
+	}
+}
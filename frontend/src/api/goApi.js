@@ -65,92 +65,107 @@ export const goApi = {
     },
 
     /**
-     * 获取12生肖配置
-     * @returns {Promise<Object>} 12生肖配置对象
+     * 校验系统配置
+     * @param {Object} config 系统配置
+     * @returns {Promise<Array>} 不合法的配置项 [{ field, message }]，合法时为空数组
      */
-    getZodiacConfig: async () => {
+    validateSystemConfig: async (config) => {
         try {
-            const result = await goApp.GetZodiacConfig();
-            return result;
+            const result = await goApp.ValidateSystemConfig(config);
+            return result || [];
         } catch (error) {
-            console.error("获取12生肖配置失败:", error);
-            return {};
+            console.error("校验系统配置失败:", error);
+            throw error;
         }
     },
 
     /**
-     * 保存12生肖配置
-     * @param {Object} config 12生肖配置对象
-     * @returns {Promise<boolean>} 是否成功
+     * 分析配置中的别名冲突
+     * @param {Object} config 系统配置
+     * @returns {Promise<Object>} 分析结果 { conflicts: 重复的别名, shadowed: 被较长别名包含的别名 }
      */
-    saveZodiacConfig: async (config) => {
+    analyzeAliases: async (config) => {
         try {
-            await goApp.SaveZodiacConfig(config);
-            return true;
+            return await goApp.AnalyzeAliases(config);
         } catch (error) {
-            console.error("保存12生肖配置失败:", error);
+            console.error("分析别名冲突失败:", error);
             throw error;
         }
     },
 
     /**
-     * 获取颜色波段配置
-     * @returns {Promise<Object>} 颜色配置对象
+     * 获取号码集合（生肖、波色、尾数等）
+     * @returns {Promise<Array>} 号码集合列表
      */
-    getColorConfig: async () => {
+    getNumberSets: async () => {
         try {
-            const result = await goApp.GetColorConfig();
-            return result;
+            const result = await goApp.GetNumberSets();
+            return result || [];
         } catch (error) {
-            console.error("获取颜色配置失败:", error);
-            return {};
+            console.error("获取号码集合失败:", error);
+            return [];
         }
     },
 
     /**
-     * 保存颜色波段配置
-     * @param {Object} config 颜色配置对象
+     * 保存号码集合
+     * @param {Array} sets 号码集合列表
      * @returns {Promise<boolean>} 是否成功
      */
-    saveColorConfig: async (config) => {
+    saveNumberSets: async (sets) => {
         try {
-            await goApp.SaveColorConfig(config);
+            await goApp.SaveNumberSets(sets);
             return true;
         } catch (error) {
-            console.error("保存颜色配置失败:", error);
+            console.error("保存号码集合失败:", error);
             throw error;
         }
     },
 
     /**
-     * 获取尾数配置
-     * @returns {Promise<Object>} 尾数配置对象
+     * 获取生肖换肖设置
+     * @returns {Promise<Object>} 换肖设置（是否自动换肖、各年农历新年日期）
      */
-    getTailConfig: async () => {
+    getZodiacSchedule: async () => {
         try {
-            const result = await goApp.GetTailConfig();
+            const result = await goApp.GetZodiacSchedule();
             return result;
         } catch (error) {
-            console.error("获取尾数配置失败:", error);
+            console.error("获取换肖设置失败:", error);
             return {};
         }
     },
 
     /**
-     * 保存尾数配置
-     * @param {Object} config 尾数配置对象
+     * 保存生肖换肖设置
+     * @param {Object} schedule 换肖设置
      * @returns {Promise<boolean>} 是否成功
      */
-    saveTailConfig: async (config) => {
+    saveZodiacSchedule: async (schedule) => {
         try {
-            await goApp.SaveTailConfig(config);
+            await goApp.SaveZodiacSchedule(schedule);
             return true;
         } catch (error) {
-            console.error("保存尾数配置失败:", error);
+            console.error("保存换肖设置失败:", error);
             throw error;
         }
     },
 
+    /**
+     * 生成生肖号码表
+     * @param {number} year 农历年份，0为当前生效的号码表
+     * @returns {Promise<Object>} 生肖号码表
+     */
+    getZodiacTable: async (year = 0) => {
+        try {
+            const result = await goApp.GetZodiacTable(year);
+            return result;
+        } catch (error) {
+            console.error("获取生肖号码表失败:", error);
+            return {};
+        }
+    },
+
     /**
      * 获取下注类型别名配置
      * @returns {Promise<Object>} 下注类型别名配置对象
@@ -252,6 +267,35 @@ export const goApi = {
         }
     },
 
+    /**
+     * 获取解析选项
+     * @returns {Promise<Object>} 解析选项对象
+     */
+    getParserOptions: async () => {
+        try {
+            const result = await goApp.GetParserOptions();
+            return result;
+        } catch (error) {
+            console.error("获取解析选项失败:", error);
+            return {};
+        }
+    },
+
+    /**
+     * 保存解析选项
+     * @param {Object} options 解析选项对象
+     * @returns {Promise<boolean>} 是否成功
+     */
+    saveParserOptions: async (options) => {
+        try {
+            await goApp.SaveParserOptions(options);
+            return true;
+        } catch (error) {
+            console.error("保存解析选项失败:", error);
+            throw error;
+        }
+    },
+
     // 移除了结算和导出Excel方法，目前不需要
 
     // ================================
@@ -288,6 +332,430 @@ export const goApi = {
             console.error("智能解析下注输入失败:", error);
             throw new Error(`智能解析失败: ${error.message || error}`);
         }
+    },
+
+    /**
+     * 按选择的理解方式智能解析下注输入
+     * @param {string} input 输入的下注字符串
+     * @param {Array<string>} enabledTypes 启用的彩种类型
+     * @param {Object<number, number>} choices 下注序号(从1开始) -> 理解方式下标
+     * @returns {Promise<Object>} 智能解析结果对象
+     */
+    parseBetInputIntelligentWithChoices: async (input, enabledTypes, choices) => {
+        try {
+            const result = await goApp.ParseBetInputIntelligentWithChoices(input, enabledTypes || [], choices || {});
+            return result;
+        } catch (error) {
+            console.error("按选择的理解方式解析失败:", error);
+            throw new Error(`智能解析失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 导入导出的群聊记录，按期数、玩家分组解析下注
+     * @param {string} content 聊天记录文本（每条消息以"昵称 2026-10-17 20:31:05"开头）
+     * @param {Object} options 导入选项 { draw_cutoff: "21:30", enabled_types: [], profile: "" }
+     * @returns {Promise<Object>} 导入结果对象
+     */
+    importChatLog: async (content, options) => {
+        try {
+            const result = await goApp.ImportChatLog(content, options || {});
+            return result;
+        } catch (error) {
+            console.error("导入聊天记录失败:", error);
+            throw new Error(`导入聊天记录失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 开始监视目录，放入的聊天记录文件(.txt/.jsonl)自动解析并记入账本
+     * 每记入一条会触发 "ledger:recorded" 事件
+     * @param {string} dir 监视的目录
+     * @returns {Promise<void>}
+     */
+    startMessageWatch: async (dir) => {
+        try {
+            await goApp.StartMessageWatch(dir);
+        } catch (error) {
+            console.error("开始监视目录失败:", error);
+            throw new Error(`开始监视目录失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 停止监视目录
+     * @returns {Promise<void>}
+     */
+    stopMessageWatch: async () => {
+        try {
+            await goApp.StopMessageWatch();
+        } catch (error) {
+            console.error("停止监视目录失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取账本中的所有记录
+     * @returns {Promise<Array>} 账本记录列表
+     */
+    getLedgerEntries: async () => {
+        try {
+            const result = await goApp.GetLedgerEntries();
+            return result || [];
+        } catch (error) {
+            console.error("获取账本记录失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 重新解析账本中的一条记录
+     * @param {string} id 账本记录ID
+     * @param {number} version 配置版本，为0时使用该记录解析时的配置版本
+     * @param {Object<number, number>} choices 下注序号(从1开始) -> 理解方式下标
+     * @returns {Promise<Object>} 智能解析结果对象
+     */
+    reparseLedgerEntry: async (id, version, choices) => {
+        try {
+            return await goApp.ReparseLedgerEntry(id, version || 0, choices || {});
+        } catch (error) {
+            console.error("重新解析账本记录失败:", error);
+            throw new Error(`重新解析失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 获取所有配置版本概要
+     * @returns {Promise<Array>} 配置版本列表 [{ version, effectiveAt }]
+     */
+    getConfigVersions: async () => {
+        try {
+            const result = await goApp.GetConfigVersions();
+            return result || [];
+        } catch (error) {
+            console.error("获取配置版本失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取指定版本的配置
+     * @param {number} version 配置版本
+     * @returns {Promise<Object>} 该版本的系统配置
+     */
+    getConfigVersion: async (version) => {
+        try {
+            return await goApp.GetConfigVersion(version);
+        } catch (error) {
+            console.error("获取配置版本失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 重新读取配置文件，内容不合法时保持当前配置
+     * 配置文件被外部修改时会自动重新加载并触发 "config:reloaded" 事件，不合法时触发 "config:rejected" 事件
+     * @returns {Promise<void>}
+     */
+    reloadSystemConfig: async () => {
+        try {
+            await goApp.ReloadSystemConfig();
+        } catch (error) {
+            console.error("重新加载配置失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取启动时配置文件不能加载的原因，此时暂用默认配置且禁止保存配置
+     * @returns {Promise<string>} 不能加载的原因，配置已正常加载时为空
+     */
+    getConfigLoadError: async () => {
+        try {
+            return await goApp.GetConfigLoadError();
+        } catch (error) {
+            console.error("获取配置加载错误失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 设置当前操作员，之后的配置修改记录该操作员
+     * @param {string} name 操作员名称，为空时使用系统用户名
+     * @returns {Promise<void>}
+     */
+    setOperator: async (name) => {
+        try {
+            await goApp.SetOperator(name || "");
+        } catch (error) {
+            console.error("设置操作员失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取配置修改记录，按时间从新到旧排列
+     * @returns {Promise<Array>} 修改记录 [{ id, section, operator, timestamp, configVersion, changes, revertOf }]
+     */
+    getConfigAuditLog: async () => {
+        try {
+            const result = await goApp.GetConfigAuditLog();
+            return result || [];
+        } catch (error) {
+            console.error("获取配置修改记录失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 撤销一次配置修改
+     * @param {number} id 修改记录ID
+     * @returns {Promise<void>}
+     */
+    revertConfigChange: async (id) => {
+        try {
+            await goApp.RevertConfigChange(id);
+        } catch (error) {
+            console.error("撤销配置修改失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取配置备份列表，按时间从新到旧排列
+     * @returns {Promise<Array>} 配置备份列表 [{ name, reason, createdAt, size }]
+     */
+    getConfigBackups: async () => {
+        try {
+            const result = await goApp.GetConfigBackups();
+            return result || [];
+        } catch (error) {
+            console.error("获取配置备份失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 从备份恢复配置
+     * @param {string} name 备份文件名
+     * @returns {Promise<void>}
+     */
+    restoreConfigBackup: async (name) => {
+        try {
+            await goApp.RestoreConfigBackup(name);
+        } catch (error) {
+            console.error("恢复配置备份失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 导出配置包
+     * @param {Array<string>} sections 配置项或分组（aliases别名、odds赔率），为空时导出全部配置
+     * @returns {Promise<string>} 配置包JSON文本
+     */
+    exportConfigBundle: async (sections) => {
+        try {
+            return await goApp.ExportConfigBundle(sections || []);
+        } catch (error) {
+            console.error("导出配置包失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 预览导入配置包后的变化
+     * @param {string} bundle 配置包JSON文本
+     * @param {string} mode 导入方式 merge合并/replace替换
+     * @returns {Promise<Object>} 预览 { mode, sections, exportedAt, changes, errors, conflicts }
+     */
+    previewConfigImport: async (bundle, mode) => {
+        try {
+            return await goApp.PreviewConfigImport(bundle, mode || "merge");
+        } catch (error) {
+            console.error("预览配置包失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 导入配置包
+     * @param {string} bundle 配置包JSON文本
+     * @param {string} mode 导入方式 merge合并/replace替换
+     * @returns {Promise<void>}
+     */
+    importConfigBundle: async (bundle, mode) => {
+        try {
+            await goApp.ImportConfigBundle(bundle, mode || "merge");
+        } catch (error) {
+            console.error("导入配置包失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取所有配置方案，当前方案在前
+     * @returns {Promise<Array>} 配置方案列表 [{ name, active }]
+     */
+    getProfiles: async () => {
+        try {
+            const result = await goApp.GetProfiles();
+            return result || [];
+        } catch (error) {
+            console.error("获取配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 获取按配置方案生效的配置，用于按该方案的赔率结算
+     * @param {string} profile 配置方案名称，为空时为当前方案
+     * @returns {Promise<Object>} 系统配置
+     */
+    getProfileConfig: async (profile) => {
+        try {
+            return await goApp.GetProfileConfig(profile || "");
+        } catch (error) {
+            console.error("获取配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 按默认配置新建配置方案
+     * @param {string} name 方案名称
+     * @returns {Promise<void>}
+     */
+    createProfile: async (name) => {
+        try {
+            await goApp.CreateProfile(name);
+        } catch (error) {
+            console.error("新建配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 复制配置方案
+     * @param {string} source 被复制的方案名称
+     * @param {string} name 新方案名称
+     * @returns {Promise<void>}
+     */
+    cloneProfile: async (source, name) => {
+        try {
+            await goApp.CloneProfile(source, name);
+        } catch (error) {
+            console.error("复制配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 切换当前配置方案
+     * @param {string} name 方案名称
+     * @returns {Promise<void>}
+     */
+    switchProfile: async (name) => {
+        try {
+            await goApp.SwitchProfile(name);
+        } catch (error) {
+            console.error("切换配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 删除配置方案，不能删除当前方案
+     * @param {string} name 方案名称
+     * @returns {Promise<void>}
+     */
+    deleteProfile: async (name) => {
+        try {
+            await goApp.DeleteProfile(name);
+        } catch (error) {
+            console.error("删除配置方案失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 按指定配置方案解析下注输入
+     * @param {string} input 输入的下注字符串
+     * @param {Array<string>} enabledTypes 启用的彩种类型
+     * @param {Object<number, number>} choices 下注序号(从1开始) -> 理解方式下标
+     * @param {string} profile 配置方案名称，为空时使用当前方案
+     * @returns {Promise<Object>} 智能解析结果对象
+     */
+    parseBetInputWithProfile: async (input, enabledTypes, choices, profile) => {
+        try {
+            return await goApp.ParseBetInputWithProfile(input, enabledTypes || [], choices || {}, profile || "");
+        } catch (error) {
+            console.error("按配置方案解析失败:", error);
+            throw new Error(`智能解析失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 按指定配置版本解析下注输入
+     * @param {string} input 输入的下注字符串
+     * @param {Array<string>} enabledTypes 启用的彩种类型
+     * @param {Object<number, number>} choices 下注序号(从1开始) -> 理解方式下标
+     * @param {number} version 配置版本，为0时使用当前配置
+     * @param {Object} zodiacTable 原解析结果中的生肖号码表(zodiacTable)，未传时按当前时间生成生肖号码
+     * @returns {Promise<Object>} 智能解析结果对象
+     */
+    parseBetInputWithConfigVersion: async (input, enabledTypes, choices, version, zodiacTable) => {
+        try {
+            return await goApp.ParseBetInputWithConfigVersion(input, enabledTypes || [], choices || {}, version || 0, zodiacTable || {});
+        } catch (error) {
+            console.error("按配置版本解析失败:", error);
+            throw new Error(`智能解析失败: ${error.message || error}`);
+        }
+    },
+
+    /**
+     * 获取回复模板
+     * @returns {Promise<Object>} 回复模板 { accepted, rejected, settled, statement }
+     */
+    getReplyTemplates: async () => {
+        try {
+            const result = await goApp.GetReplyTemplates();
+            return result;
+        } catch (error) {
+            console.error("获取回复模板失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 保存回复模板
+     * @param {Object} templates 回复模板
+     * @returns {Promise<void>}
+     */
+    saveReplyTemplates: async (templates) => {
+        try {
+            await goApp.SaveReplyTemplates(templates);
+        } catch (error) {
+            console.error("保存回复模板失败:", error);
+            throw error;
+        }
+    },
+
+    /**
+     * 按回复模板生成一轮下注的回复文本
+     * @param {Object} result 智能解析结果
+     * @param {string} kind 回复类型 accepted/rejected/settled/statement，为空时按解析结果选择
+     * @param {number|string} payout 中奖金额，结算及对账单使用
+     * @returns {Promise<string>} 回复文本
+     */
+    renderReply: async (result, kind, payout) => {
+        try {
+            return await goApp.RenderReply({ kind: kind || "", result, payout: payout || 0 });
+        } catch (error) {
+            console.error("生成回复失败:", error);
+            throw new Error(`生成回复失败: ${error.message || error}`);
+        }
     }
 };
 
@@ -2,23 +2,57 @@
 // This file is automatically generated. DO NOT EDIT
 import {backend} from '../models';
 
+export function AnalyzeAliases(arg1:backend.SystemConfig):Promise<backend.AliasReport>;
+
 export function Authorize(arg1:string):Promise<boolean>;
 
+export function CloneProfile(arg1:string,arg2:string):Promise<void>;
+
+export function CreateProfile(arg1:string):Promise<void>;
+
+export function DeleteProfile(arg1:string):Promise<void>;
+
+export function ExportConfigBundle(arg1:Array<string>):Promise<string>;
+
 export function GetAuthStatus():Promise<Record<string, any>>;
 
 export function GetBetTypeAliases():Promise<backend.BetTypeAliases>;
 
-export function GetColorConfig():Promise<backend.ColorConfig>;
+export function GetConfigAuditLog():Promise<Array<backend.ConfigAuditEntry>>;
+
+export function GetConfigBackups():Promise<Array<backend.ConfigBackupInfo>>;
+
+export function GetConfigLoadError():Promise<string>;
+
+export function GetConfigVersion(arg1:number):Promise<backend.SystemConfig>;
+
+export function GetConfigVersions():Promise<Array<backend.ConfigVersionInfo>>;
 
 export function GetKeywordAliases():Promise<backend.KeywordAliases>;
 
+export function GetLedgerEntries():Promise<Array<backend.LedgerEntry>>;
+
+export function GetNumberSets():Promise<Array<backend.NumberSet>>;
+
 export function GetOddsConfig():Promise<backend.OddsConfig>;
 
+export function GetParserOptions():Promise<backend.ParserOptions>;
+
+export function GetProfileConfig(arg1:string):Promise<backend.SystemConfig>;
+
+export function GetProfiles():Promise<Array<backend.ProfileInfo>>;
+
+export function GetReplyTemplates():Promise<backend.ReplyTemplates>;
+
 export function GetSystemConfig():Promise<backend.SystemConfig>;
 
-export function GetTailConfig():Promise<backend.TailConfig>;
+export function GetZodiacSchedule():Promise<backend.ZodiacSchedule>;
+
+export function GetZodiacTable(arg1:number):Promise<backend.ZodiacTable>;
+
+export function ImportChatLog(arg1:string,arg2:backend.ChatImportOptions):Promise<backend.ChatImportResult>;
 
-export function GetZodiacConfig():Promise<backend.ZodiacConfig>;
+export function ImportConfigBundle(arg1:string,arg2:string):Promise<void>;
 
 export function IsAuthorized():Promise<boolean>;
 
@@ -26,16 +60,46 @@ export function ParseBetInput(arg1:string,arg2:Array<string>):Promise<backend.Be
 
 export function ParseBetInputIntelligent(arg1:string,arg2:Array<string>):Promise<backend.BetParsingResult>;
 
+export function ParseBetInputIntelligentWithChoices(arg1:string,arg2:Array<string>,arg3:{[key: number]: number}):Promise<backend.BetParsingResult>;
+
+export function ParseBetInputWithConfigVersion(arg1:string,arg2:Array<string>,arg3:{[key: number]: number},arg4:number,arg5:backend.ZodiacTable):Promise<backend.BetParsingResult>;
+
+export function ParseBetInputWithProfile(arg1:string,arg2:Array<string>,arg3:{[key: number]: number},arg4:string):Promise<backend.BetParsingResult>;
+
+export function PreviewConfigImport(arg1:string,arg2:string):Promise<backend.ConfigImportPreview>;
+
+export function ReloadSystemConfig():Promise<void>;
+
+export function RenderReply(arg1:backend.ReplyRenderRequest):Promise<string>;
+
+export function ReparseLedgerEntry(arg1:string,arg2:number,arg3:{[key: number]: number}):Promise<backend.BetParsingResult>;
+
 export function ResetSystemConfig():Promise<void>;
 
-export function SaveBetTypeAliases(arg1:backend.BetTypeAliases):Promise<void>;
+export function RestoreConfigBackup(arg1:string):Promise<void>;
 
-export function SaveColorConfig(arg1:backend.ColorConfig):Promise<void>;
+export function RevertConfigChange(arg1:number):Promise<void>;
+
+export function SaveBetTypeAliases(arg1:backend.BetTypeAliases):Promise<void>;
 
 export function SaveKeywordAliases(arg1:backend.KeywordAliases):Promise<void>;
 
+export function SaveNumberSets(arg1:Array<backend.NumberSet>):Promise<void>;
+
 export function SaveOddsConfig(arg1:backend.OddsConfig):Promise<void>;
 
-export function SaveTailConfig(arg1:backend.TailConfig):Promise<void>;
+export function SaveParserOptions(arg1:backend.ParserOptions):Promise<void>;
+
+export function SaveReplyTemplates(arg1:backend.ReplyTemplates):Promise<void>;
+
+export function SaveZodiacSchedule(arg1:backend.ZodiacSchedule):Promise<void>;
+
+export function SetOperator(arg1:string):Promise<void>;
+
+export function StartMessageWatch(arg1:string):Promise<void>;
+
+export function StopMessageWatch():Promise<void>;
+
+export function SwitchProfile(arg1:string):Promise<void>;
 
-export function SaveZodiacConfig(arg1:backend.ZodiacConfig):Promise<void>;
+export function ValidateSystemConfig(arg1:backend.SystemConfig):Promise<Array<backend.ConfigFieldError>>;
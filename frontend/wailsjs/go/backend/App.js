@@ -2,10 +2,30 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AnalyzeAliases(arg1) {
+  return window['go']['backend']['App']['AnalyzeAliases'](arg1);
+}
+
 export function Authorize(arg1) {
   return window['go']['backend']['App']['Authorize'](arg1);
 }
 
+export function CloneProfile(arg1, arg2) {
+  return window['go']['backend']['App']['CloneProfile'](arg1, arg2);
+}
+
+export function CreateProfile(arg1) {
+  return window['go']['backend']['App']['CreateProfile'](arg1);
+}
+
+export function DeleteProfile(arg1) {
+  return window['go']['backend']['App']['DeleteProfile'](arg1);
+}
+
+export function ExportConfigBundle(arg1) {
+  return window['go']['backend']['App']['ExportConfigBundle'](arg1);
+}
+
 export function GetAuthStatus() {
   return window['go']['backend']['App']['GetAuthStatus']();
 }
@@ -14,28 +34,76 @@ export function GetBetTypeAliases() {
   return window['go']['backend']['App']['GetBetTypeAliases']();
 }
 
-export function GetColorConfig() {
-  return window['go']['backend']['App']['GetColorConfig']();
+export function GetConfigAuditLog() {
+  return window['go']['backend']['App']['GetConfigAuditLog']();
+}
+
+export function GetConfigBackups() {
+  return window['go']['backend']['App']['GetConfigBackups']();
+}
+
+export function GetConfigLoadError() {
+  return window['go']['backend']['App']['GetConfigLoadError']();
+}
+
+export function GetConfigVersion(arg1) {
+  return window['go']['backend']['App']['GetConfigVersion'](arg1);
+}
+
+export function GetConfigVersions() {
+  return window['go']['backend']['App']['GetConfigVersions']();
 }
 
 export function GetKeywordAliases() {
   return window['go']['backend']['App']['GetKeywordAliases']();
 }
 
+export function GetLedgerEntries() {
+  return window['go']['backend']['App']['GetLedgerEntries']();
+}
+
+export function GetNumberSets() {
+  return window['go']['backend']['App']['GetNumberSets']();
+}
+
 export function GetOddsConfig() {
   return window['go']['backend']['App']['GetOddsConfig']();
 }
 
+export function GetParserOptions() {
+  return window['go']['backend']['App']['GetParserOptions']();
+}
+
+export function GetProfileConfig(arg1) {
+  return window['go']['backend']['App']['GetProfileConfig'](arg1);
+}
+
+export function GetProfiles() {
+  return window['go']['backend']['App']['GetProfiles']();
+}
+
+export function GetReplyTemplates() {
+  return window['go']['backend']['App']['GetReplyTemplates']();
+}
+
 export function GetSystemConfig() {
   return window['go']['backend']['App']['GetSystemConfig']();
 }
 
-export function GetTailConfig() {
-  return window['go']['backend']['App']['GetTailConfig']();
+export function GetZodiacSchedule() {
+  return window['go']['backend']['App']['GetZodiacSchedule']();
+}
+
+export function GetZodiacTable(arg1) {
+  return window['go']['backend']['App']['GetZodiacTable'](arg1);
+}
+
+export function ImportChatLog(arg1, arg2) {
+  return window['go']['backend']['App']['ImportChatLog'](arg1, arg2);
 }
 
-export function GetZodiacConfig() {
-  return window['go']['backend']['App']['GetZodiacConfig']();
+export function ImportConfigBundle(arg1, arg2) {
+  return window['go']['backend']['App']['ImportConfigBundle'](arg1, arg2);
 }
 
 export function IsAuthorized() {
@@ -50,30 +118,90 @@ export function ParseBetInputIntelligent(arg1, arg2) {
   return window['go']['backend']['App']['ParseBetInputIntelligent'](arg1, arg2);
 }
 
+export function ParseBetInputIntelligentWithChoices(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['ParseBetInputIntelligentWithChoices'](arg1, arg2, arg3);
+}
+
+export function ParseBetInputWithConfigVersion(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['backend']['App']['ParseBetInputWithConfigVersion'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ParseBetInputWithProfile(arg1, arg2, arg3, arg4) {
+  return window['go']['backend']['App']['ParseBetInputWithProfile'](arg1, arg2, arg3, arg4);
+}
+
+export function PreviewConfigImport(arg1, arg2) {
+  return window['go']['backend']['App']['PreviewConfigImport'](arg1, arg2);
+}
+
+export function ReloadSystemConfig() {
+  return window['go']['backend']['App']['ReloadSystemConfig']();
+}
+
+export function RenderReply(arg1) {
+  return window['go']['backend']['App']['RenderReply'](arg1);
+}
+
+export function ReparseLedgerEntry(arg1, arg2, arg3) {
+  return window['go']['backend']['App']['ReparseLedgerEntry'](arg1, arg2, arg3);
+}
+
 export function ResetSystemConfig() {
   return window['go']['backend']['App']['ResetSystemConfig']();
 }
 
-export function SaveBetTypeAliases(arg1) {
-  return window['go']['backend']['App']['SaveBetTypeAliases'](arg1);
+export function RestoreConfigBackup(arg1) {
+  return window['go']['backend']['App']['RestoreConfigBackup'](arg1);
+}
+
+export function RevertConfigChange(arg1) {
+  return window['go']['backend']['App']['RevertConfigChange'](arg1);
 }
 
-export function SaveColorConfig(arg1) {
-  return window['go']['backend']['App']['SaveColorConfig'](arg1);
+export function SaveBetTypeAliases(arg1) {
+  return window['go']['backend']['App']['SaveBetTypeAliases'](arg1);
 }
 
 export function SaveKeywordAliases(arg1) {
   return window['go']['backend']['App']['SaveKeywordAliases'](arg1);
 }
 
+export function SaveNumberSets(arg1) {
+  return window['go']['backend']['App']['SaveNumberSets'](arg1);
+}
+
 export function SaveOddsConfig(arg1) {
   return window['go']['backend']['App']['SaveOddsConfig'](arg1);
 }
 
-export function SaveTailConfig(arg1) {
-  return window['go']['backend']['App']['SaveTailConfig'](arg1);
+export function SaveParserOptions(arg1) {
+  return window['go']['backend']['App']['SaveParserOptions'](arg1);
+}
+
+export function SaveReplyTemplates(arg1) {
+  return window['go']['backend']['App']['SaveReplyTemplates'](arg1);
+}
+
+export function SaveZodiacSchedule(arg1) {
+  return window['go']['backend']['App']['SaveZodiacSchedule'](arg1);
+}
+
+export function SetOperator(arg1) {
+  return window['go']['backend']['App']['SetOperator'](arg1);
+}
+
+export function StartMessageWatch(arg1) {
+  return window['go']['backend']['App']['StartMessageWatch'](arg1);
+}
+
+export function StopMessageWatch() {
+  return window['go']['backend']['App']['StopMessageWatch']();
+}
+
+export function SwitchProfile(arg1) {
+  return window['go']['backend']['App']['SwitchProfile'](arg1);
 }
 
-export function SaveZodiacConfig(arg1) {
-  return window['go']['backend']['App']['SaveZodiacConfig'](arg1);
+export function ValidateSystemConfig(arg1) {
+  return window['go']['backend']['App']['ValidateSystemConfig'](arg1);
 }
@@ -374,11 +374,17 @@ export namespace backend {
 	}
 	export class SingleBetParsing {
 	    betId: string;
+	    player: string;
 	    originalText: string;
 	    lotteryBets: Record<string, LotteryBetInfo>;
 	    betStatistics: BetStatistics;
 	    hasError: boolean;
 	    errorMessage: string[];
+	    warnings: string[];
+	    declaredGroups: number;
+	    // Go type: decimal
+	    declaredTotal: any;
+	    ignoredText: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new SingleBetParsing(source);
@@ -387,13 +393,97 @@ export namespace backend {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.betId = source["betId"];
+	        this.player = source["player"];
 	        this.originalText = source["originalText"];
 	        this.lotteryBets = this.convertValues(source["lotteryBets"], LotteryBetInfo, true);
 	        this.betStatistics = this.convertValues(source["betStatistics"], BetStatistics);
 	        this.hasError = source["hasError"];
 	        this.errorMessage = source["errorMessage"];
+	        this.warnings = source["warnings"];
+	        this.declaredGroups = source["declaredGroups"];
+	        this.declaredTotal = this.convertValues(source["declaredTotal"], null);
+	        this.ignoredText = source["ignoredText"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BetInterpretation {
+	    label: string;
+	    text: string;
+	    confidence: number;
+	    totalGroups: number;
+	    // Go type: decimal
+	    totalAmount: any;
+	    needAmount: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BetInterpretation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.text = source["text"];
+	        this.confidence = source["confidence"];
+	        this.totalGroups = source["totalGroups"];
+	        this.totalAmount = this.convertValues(source["totalAmount"], null);
+	        this.needAmount = source["needAmount"];
 	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BetAmbiguity {
+	    betId: string;
+	    betIndex: number;
+	    originalText: string;
+	    interpretations: BetInterpretation[];
+	    selected: number;
 	
+	    static createFrom(source: any = {}) {
+	        return new BetAmbiguity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.betId = source["betId"];
+	        this.betIndex = source["betIndex"];
+	        this.originalText = source["originalText"];
+	        this.interpretations = this.convertValues(source["interpretations"], BetInterpretation);
+	        this.selected = source["selected"];
+	    }
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -412,8 +502,25 @@ export namespace backend {
 		    return a;
 		}
 	}
+	export class ZodiacTable {
+	    lunarYear: number;
+	    yearZodiac: string;
+	    numbers: Record<string, Array<number>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZodiacTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lunarYear = source["lunarYear"];
+	        this.yearZodiac = source["yearZodiac"];
+	        this.numbers = source["numbers"];
+	    }
+	}
 	export class BetParsingResult {
 	    roundId: string;
+	    player: string;
 	    originalText: string;
 	    parsedBets: SingleBetParsing[];
 	    roundStatistics: RoundBetStatistics;
@@ -421,6 +528,12 @@ export namespace backend {
 	    parseTime: any;
 	    hasError: boolean;
 	    errorMessages: string[];
+	    warningMessages: string[];
+	    ambiguities: BetAmbiguity[];
+	    playerRounds: BetParsingResult[];
+	    zodiacTable: ZodiacTable;
+	    configVersion: number;
+	    profile: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new BetParsingResult(source);
@@ -429,12 +542,19 @@ export namespace backend {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.roundId = source["roundId"];
+	        this.player = source["player"];
 	        this.originalText = source["originalText"];
 	        this.parsedBets = this.convertValues(source["parsedBets"], SingleBetParsing);
 	        this.roundStatistics = this.convertValues(source["roundStatistics"], RoundBetStatistics);
 	        this.parseTime = this.convertValues(source["parseTime"], null);
 	        this.hasError = source["hasError"];
 	        this.errorMessages = source["errorMessages"];
+	        this.warningMessages = source["warningMessages"];
+	        this.ambiguities = this.convertValues(source["ambiguities"], BetAmbiguity);
+	        this.playerRounds = this.convertValues(source["playerRounds"], BetParsingResult);
+	        this.zodiacTable = this.convertValues(source["zodiacTable"], ZodiacTable);
+	        this.configVersion = source["configVersion"];
+	        this.profile = source["profile"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -478,22 +598,6 @@ export namespace backend {
 	
 	
 	
-	export class ColorConfig {
-	    red: number[];
-	    green: number[];
-	    blue: number[];
-	
-	    static createFrom(source: any = {}) {
-	        return new ColorConfig(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.red = source["red"];
-	        this.green = source["green"];
-	        this.blue = source["blue"];
-	    }
-	}
 	export class HitThreeOdds {
 	    odds_ratio: number;
 	    rebate: number;
@@ -621,6 +725,24 @@ export namespace backend {
 	        this.rebate = source["rebate"];
 	    }
 	}
+	export class NumberSet {
+	    name: string;
+	    category: string;
+	    aliases: string[];
+	    numbers: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NumberSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.category = source["category"];
+	        this.aliases = source["aliases"];
+	        this.numbers = source["numbers"];
+	    }
+	}
 	export class OddsConfig {
 	    three_of_three: ThreeOfThreeOdds;
 	    three_of_two: ThreeOfTwoOdds;
@@ -661,77 +783,141 @@ export namespace backend {
 	
 	
 	
-	export class TailConfig {
-	    tail_0: number[];
-	    tail_1: number[];
-	    tail_2: number[];
-	    tail_3: number[];
-	    tail_4: number[];
-	    tail_5: number[];
-	    tail_6: number[];
-	    tail_7: number[];
-	    tail_8: number[];
-	    tail_9: number[];
+	export class WeChatFilterOptions {
+	    quoted_replies: boolean;
+	    mentions: boolean;
+	    emoji_codes: boolean;
+	    recall_notices: boolean;
+	    sender_headers: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WeChatFilterOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.quoted_replies = source["quoted_replies"];
+	        this.mentions = source["mentions"];
+	        this.emoji_codes = source["emoji_codes"];
+	        this.recall_notices = source["recall_notices"];
+	        this.sender_headers = source["sender_headers"];
+	    }
+	}
+	export class ParserOptions {
+	    strict_unknown_text: boolean;
+	    wechat_filter: WeChatFilterOptions;
+	    player_header_patterns: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ParserOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.strict_unknown_text = source["strict_unknown_text"];
+	        this.wechat_filter = this.convertValues(source["wechat_filter"], WeChatFilterOptions);
+	        this.player_header_patterns = source["player_header_patterns"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplyTemplates {
+	    accepted: string;
+	    rejected: string;
+	    settled: string;
+	    statement: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new TailConfig(source);
+	        return new ReplyTemplates(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.tail_0 = source["tail_0"];
-	        this.tail_1 = source["tail_1"];
-	        this.tail_2 = source["tail_2"];
-	        this.tail_3 = source["tail_3"];
-	        this.tail_4 = source["tail_4"];
-	        this.tail_5 = source["tail_5"];
-	        this.tail_6 = source["tail_6"];
-	        this.tail_7 = source["tail_7"];
-	        this.tail_8 = source["tail_8"];
-	        this.tail_9 = source["tail_9"];
+	        this.accepted = source["accepted"];
+	        this.rejected = source["rejected"];
+	        this.settled = source["settled"];
+	        this.statement = source["statement"];
 	    }
 	}
-	export class ZodiacConfig {
-	    rat: number[];
-	    ox: number[];
-	    tiger: number[];
-	    rabbit: number[];
-	    dragon: number[];
-	    snake: number[];
-	    horse: number[];
-	    goat: number[];
-	    monkey: number[];
-	    rooster: number[];
-	    dog: number[];
-	    pig: number[];
+	export class ZodiacSchedule {
+	    auto_update: boolean;
+	    new_year_dates: Record<string, string>;
 	
 	    static createFrom(source: any = {}) {
-	        return new ZodiacConfig(source);
+	        return new ZodiacSchedule(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.rat = source["rat"];
-	        this.ox = source["ox"];
-	        this.tiger = source["tiger"];
-	        this.rabbit = source["rabbit"];
-	        this.dragon = source["dragon"];
-	        this.snake = source["snake"];
-	        this.horse = source["horse"];
-	        this.goat = source["goat"];
-	        this.monkey = source["monkey"];
-	        this.rooster = source["rooster"];
-	        this.dog = source["dog"];
-	        this.pig = source["pig"];
+	        this.auto_update = source["auto_update"];
+	        this.new_year_dates = source["new_year_dates"];
 	    }
 	}
+	export class ConfigProfile {
+	    name: string;
+	    number_sets: NumberSet[];
+	    bet_type_aliases: BetTypeAliases;
+	    keyword_aliases: KeywordAliases;
+	    odds_config: OddsConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.number_sets = this.convertValues(source["number_sets"], NumberSet);
+	        this.bet_type_aliases = this.convertValues(source["bet_type_aliases"], BetTypeAliases);
+	        this.keyword_aliases = this.convertValues(source["keyword_aliases"], KeywordAliases);
+	        this.odds_config = this.convertValues(source["odds_config"], OddsConfig);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SystemConfig {
-	    zodiac_config: ZodiacConfig;
-	    color_config: ColorConfig;
-	    tail_config: TailConfig;
+	    schema_version: number;
+	    active_profile: string;
+	    profiles: ConfigProfile[];
+	    number_sets: NumberSet[];
+	    zodiac_schedule: ZodiacSchedule;
 	    bet_type_aliases: BetTypeAliases;
 	    keyword_aliases: KeywordAliases;
 	    odds_config: OddsConfig;
+	    parser_options: ParserOptions;
+	    reply_templates: ReplyTemplates;
 	
 	    static createFrom(source: any = {}) {
 	        return new SystemConfig(source);
@@ -739,14 +925,260 @@ export namespace backend {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.zodiac_config = this.convertValues(source["zodiac_config"], ZodiacConfig);
-	        this.color_config = this.convertValues(source["color_config"], ColorConfig);
-	        this.tail_config = this.convertValues(source["tail_config"], TailConfig);
+	        this.schema_version = source["schema_version"];
+	        this.active_profile = source["active_profile"];
+	        this.profiles = this.convertValues(source["profiles"], ConfigProfile);
+	        this.number_sets = this.convertValues(source["number_sets"], NumberSet);
+	        this.zodiac_schedule = this.convertValues(source["zodiac_schedule"], ZodiacSchedule);
 	        this.bet_type_aliases = this.convertValues(source["bet_type_aliases"], BetTypeAliases);
 	        this.keyword_aliases = this.convertValues(source["keyword_aliases"], KeywordAliases);
 	        this.odds_config = this.convertValues(source["odds_config"], OddsConfig);
+	        this.parser_options = this.convertValues(source["parser_options"], ParserOptions);
+	        this.reply_templates = this.convertValues(source["reply_templates"], ReplyTemplates);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+
+	export class ChatMessage {
+	    sender: string;
+	    // Go type: time
+	    time: any;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sender = source["sender"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.text = source["text"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatImportOptions {
+	    draw_cutoff: string;
+	    enabled_types: string[];
+	    profile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.draw_cutoff = source["draw_cutoff"];
+	        this.enabled_types = source["enabled_types"];
+	        this.profile = source["profile"];
+	    }
+	}
+	export class PlayerBetBatch {
+	    player: string;
+	    period: string;
+	    messages: ChatMessage[];
+	    result: BetParsingResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayerBetBatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.player = source["player"];
+	        this.period = source["period"];
+	        this.messages = this.convertValues(source["messages"], ChatMessage);
+	        this.result = this.convertValues(source["result"], BetParsingResult);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatImportResult {
+	    batches: PlayerBetBatch[];
+	    messageCount: number;
+	    skippedMessages: ChatMessage[];
+	    // Go type: decimal
+	    totalAmount: any;
+	    hasError: boolean;
+	    warningMessages: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.batches = this.convertValues(source["batches"], PlayerBetBatch);
+	        this.messageCount = source["messageCount"];
+	        this.skippedMessages = this.convertValues(source["skippedMessages"], ChatMessage);
+	        this.totalAmount = this.convertValues(source["totalAmount"], null);
+	        this.hasError = source["hasError"];
+	        this.warningMessages = source["warningMessages"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IncomingMessage {
+	    chat: string;
+	    sender: string;
+	    // Go type: time
+	    time: any;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IncomingMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.chat = source["chat"];
+	        this.sender = source["sender"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.text = source["text"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LedgerEntry {
+	    id: string;
+	    message: IncomingMessage;
+	    result: BetParsingResult;
+	    // Go type: time
+	    recordedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LedgerEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.message = this.convertValues(source["message"], IncomingMessage);
+	        this.result = this.convertValues(source["result"], BetParsingResult);
+	        this.recordedAt = this.convertValues(source["recordedAt"], null);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplyRenderRequest {
+	    kind: string;
+	    result: BetParsingResult;
+	    // Go type: decimal
+	    payout: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplyRenderRequest(source);
 	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.result = this.convertValues(source["result"], BetParsingResult);
+	        this.payout = this.convertValues(source["payout"], null);
+	    }
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -765,10 +1197,257 @@ export namespace backend {
 		    return a;
 		}
 	}
+	export class ConfigBackupInfo {
+	    name: string;
+	    reason: string;
+	    // Go type: time
+	    createdAt: any;
+	    size: number;
 	
+	    static createFrom(source: any = {}) {
+	        return new ConfigBackupInfo(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.reason = source["reason"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.size = source["size"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigVersionInfo {
+	    version: number;
+	    // Go type: time
+	    effectiveAt: any;
 	
+	    static createFrom(source: any = {}) {
+	        return new ConfigVersionInfo(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.effectiveAt = this.convertValues(source["effectiveAt"], null);
+	    }
 
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigFieldError {
+	    field: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigFieldError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.message = source["message"];
+	    }
+	}
+	export class AliasConflict {
+	    kind: string;
+	    keyword: string;
+	    owners: string[];
+	    within: string;
+	    profile: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AliasConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.keyword = source["keyword"];
+	        this.owners = source["owners"];
+	        this.within = source["within"];
+	        this.profile = source["profile"];
+	        this.message = source["message"];
+	    }
+	}
+	export class AliasReport {
+	    conflicts: AliasConflict[];
+	    shadowed: AliasConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AliasReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conflicts = this.convertValues(source["conflicts"], AliasConflict);
+	        this.shadowed = this.convertValues(source["shadowed"], AliasConflict);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigChange {
+	    path: string;
+	    before: string;
+	    after: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	}
+	export class ConfigImportPreview {
+	    mode: string;
+	    sections: string[];
+	    // Go type: time
+	    exportedAt: any;
+	    changes: ConfigChange[];
+	    errors: ConfigFieldError[];
+	    conflicts: AliasConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.sections = source["sections"];
+	        this.exportedAt = this.convertValues(source["exportedAt"], null);
+	        this.changes = this.convertValues(source["changes"], ConfigChange);
+	        this.errors = this.convertValues(source["errors"], ConfigFieldError);
+	        this.conflicts = this.convertValues(source["conflicts"], AliasConflict);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfileInfo {
+	    name: string;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.active = source["active"];
+	    }
+	}
+	export class ConfigAuditEntry {
+	    id: number;
+	    section: string;
+	    operator: string;
+	    // Go type: time
+	    timestamp: any;
+	    configVersion: number;
+	    changes: ConfigChange[];
+	    revertOf: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigAuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.section = source["section"];
+	        this.operator = source["operator"];
+	        this.timestamp = this.convertValues(source["timestamp"], null);
+	        this.configVersion = source["configVersion"];
+	        this.changes = this.convertValues(source["changes"], ConfigChange);
+	        this.revertOf = source["revertOf"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 }
 